@@ -3,6 +3,9 @@ module github.com/federicoReghini/gator
 go 1.24.5
 
 require (
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/lib/pq v1.10.9 // indirect
+	github.com/google/uuid v1.6.0
+	github.com/lib/pq v1.10.9
+	golang.org/x/net v0.38.0
 )
+
+require golang.org/x/text v0.23.0 // indirect
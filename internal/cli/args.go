@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseArgs splits the arguments of a command into positional arguments and
+// "--name value" (or "--name=value") flags. Flags listed in boolFlags don't
+// take a value and are set to "true" when present.
+func parseArgs(args []string, boolFlags ...string) ([]string, map[string]string, error) {
+	var positional []string
+	flags := map[string]string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, "--")
+		if name, value, found := strings.Cut(name, "="); found {
+			flags[name] = value
+			continue
+		}
+
+		if slices.Contains(boolFlags, name) {
+			flags[name] = "true"
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, nil, fmt.Errorf("missing value for --%s", name)
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+
+	return positional, flags, nil
+}
+
+func intFlag(flags map[string]string, name string, fallback int) (int, error) {
+	value, ok := flags[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid value for --%s: %s", name, value)
+	}
+	return n, nil
+}
+
+// parseDuration is time.ParseDuration with support for a "d" suffix, so that
+// ages such as "7d" or "90d" can be written without converting to hours.
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func durationFlag(flags map[string]string, name string, fallback time.Duration) (time.Duration, error) {
+	value, ok := flags[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	d, err := parseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid value for --%s: %s", name, value)
+	}
+	return d, nil
+}
+
+// timeFlag parses a date (2006-01-02), an RFC 3339 time or an age such as
+// "7d", meaning that long ago.
+func timeFlag(flags map[string]string, name string) (sql.NullTime, error) {
+	value, ok := flags[name]
+	if !ok {
+		return sql.NullTime{}, nil
+	}
+
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return sql.NullTime{Time: t.UTC(), Valid: true}, nil
+		}
+	}
+	if d, err := parseDuration(value); err == nil && d > 0 {
+		return sql.NullTime{Time: time.Now().UTC().Add(-d), Valid: true}, nil
+	}
+
+	return sql.NullTime{}, fmt.Errorf("invalid value for --%s: %s", name, value)
+}
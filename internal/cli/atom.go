@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    AtomText    `xml:"title"`
+	Subtitle AtomText    `xml:"subtitle"`
+	Links    []AtomLink  `xml:"link"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+type AtomEntry struct {
+	ID        string       `xml:"id"`
+	Title     AtomText     `xml:"title"`
+	Links     []AtomLink   `xml:"link"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Summary   AtomText     `xml:"summary"`
+	Content   AtomText     `xml:"content"`
+	Authors   []AtomPerson `xml:"author"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// AtomText holds an Atom text construct. Plain text and escaped html end up
+// in Text, while type="xhtml" keeps the markup as is in InnerXML.
+type AtomText struct {
+	Type     string `xml:"type,attr"`
+	Text     string `xml:",chardata"`
+	InnerXML string `xml:",innerxml"`
+}
+
+func (t AtomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.InnerXML)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// alternateLink returns the href of the rel="alternate" link (the default
+// when rel is missing), falling back to the first link of the list.
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+func enclosureLinks(links []AtomLink) []RSSEnclosure {
+	var enclosures []RSSEnclosure
+	for _, link := range links {
+		if link.Rel == "enclosure" {
+			enclosures = append(enclosures, RSSEnclosure{
+				URL:    link.Href,
+				Length: link.Length,
+				Type:   link.Type,
+			})
+		}
+	}
+	return enclosures
+}
+
+func (f *AtomFeed) toRSSFeed() *RSSFeed {
+	var feed RSSFeed
+	feed.Channel.Title = f.Title.String()
+	feed.Channel.Link = alternateLink(f.Links)
+	feed.Channel.Description = f.Subtitle.String()
+
+	for _, entry := range f.Entries {
+		pubDate := entry.Published
+		if pubDate == "" {
+			pubDate = entry.Updated
+		}
+
+		description := entry.Summary.String()
+		if description == "" {
+			description = entry.Content.String()
+		}
+
+		var authors []string
+		for _, author := range entry.Authors {
+			authors = append(authors, strings.TrimSpace(author.Name))
+		}
+
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			GUID:        strings.TrimSpace(entry.ID),
+			Title:       entry.Title.String(),
+			Link:        alternateLink(entry.Links),
+			Description: description,
+			PubDate:     strings.TrimSpace(pubDate),
+			Updated:     strings.TrimSpace(entry.Updated),
+			Content:     entry.Content.String(),
+			Enclosures:  enclosureLinks(entry.Links),
+			Creator:     strings.Join(authors, ", "),
+		})
+	}
+
+	return &feed
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/state"
+)
+
+const (
+	minBackoff = 5 * time.Minute
+	maxBackoff = 24 * time.Hour
+	// a Retry-After further away than this is most likely a misconfiguration
+	maxRetryAfter = 7 * 24 * time.Hour
+)
+
+// backoff returns how long to wait before fetching again a feed that failed
+// failures times in a row: it doubles from minBackoff up to maxBackoff, unless
+// the server asked us to wait even longer with Retry-After.
+func backoff(failures int32, retryAfter time.Duration) time.Duration {
+	delay := maxBackoff
+	if failures < 10 {
+		delay = min(minBackoff<<max(failures-1, 0), maxBackoff)
+	}
+
+	return max(delay, min(retryAfter, maxRetryAfter))
+}
+
+// parseRetryAfter reads a Retry-After header, given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+
+	return 0
+}
+
+func Enable(s *state.State, cmd Command) error {
+	if len(cmd.Args) != 1 {
+		return fmt.Errorf("usage: %s <feed_url>", cmd.Name)
+	}
+
+	feed, err := s.Db.EnableFeed(context.Background(), sql.NullString{String: cmd.Args[0], Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no feed found with url %s", cmd.Args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't enable feed: %w", err)
+	}
+
+	fmt.Printf("Feed %s enabled, it will be fetched on the next agg tick.\n", feed.Name)
+	return nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures   int32
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{0, 0, minBackoff},
+		{1, 0, minBackoff},
+		{2, 0, 2 * minBackoff},
+		{4, 0, 8 * minBackoff},
+		{9, 0, 256 * minBackoff},
+		{10, 0, maxBackoff},
+		{50, 0, maxBackoff},
+		{1, time.Hour, time.Hour},
+		{4, time.Minute, 8 * minBackoff},
+		{1, 30 * 24 * time.Hour, maxRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.failures, tt.retryAfter); got != tt.want {
+			t.Errorf("backoff(%d, %s) = %s, want %s", tt.failures, tt.retryAfter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+func Category(s *state.State, cmd Command, user database.User) error {
+	usage := fmt.Errorf("usage: %s add <name> | rm <name> | mv <feed_url|feed_name> <name|->", cmd.Name)
+	if len(cmd.Args) == 0 {
+		return usage
+	}
+
+	switch args := cmd.Args[1:]; {
+	case cmd.Args[0] == "add" && len(args) == 1:
+		category, err := upsertCategory(s, user, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Category %s is ready\n", category.Name)
+		return nil
+
+	case cmd.Args[0] == "rm" && len(args) == 1:
+		rules, err := s.Db.GetIngestRuleNamesForCategory(context.Background(), database.GetIngestRuleNamesForCategoryParams{
+			UserID: user.ID,
+			Name:   args[0],
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't get rules of category: %w", err)
+		}
+		if len(rules) > 0 {
+			return fmt.Errorf("category %s is used by rules %s, remove them first with rule rm", args[0], strings.Join(rules, ", "))
+		}
+
+		deleted, err := s.Db.DeleteCategory(context.Background(), database.DeleteCategoryParams{
+			UserID: user.ID,
+			Name:   args[0],
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't delete category: %w", err)
+		}
+		if deleted == 0 {
+			return fmt.Errorf("no category named %s", args[0])
+		}
+		fmt.Printf("Deleted category %s, its feeds are now uncategorized\n", args[0])
+		return nil
+
+	case cmd.Args[0] == "mv" && len(args) == 2:
+		return moveToCategory(s, user, args[0], args[1])
+
+	default:
+		return usage
+	}
+}
+
+func upsertCategory(s *state.State, user database.User, name string) (database.Category, error) {
+	category, err := s.Db.UpsertCategory(context.Background(), database.UpsertCategoryParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		Name:      name,
+	})
+	if err != nil {
+		return database.Category{}, fmt.Errorf("couldn't create category %s: %w", name, err)
+	}
+	return category, nil
+}
+
+// moveToCategory files a followed feed under the named category, creating it
+// if needed. The name "-" takes the feed out of its category.
+func moveToCategory(s *state.State, user database.User, feed, name string) error {
+	follow, err := findFollowedFeed(s, user, feed)
+	if err != nil {
+		return err
+	}
+
+	var categoryID uuid.NullUUID
+	if name != "-" {
+		category, err := upsertCategory(s, user, name)
+		if err != nil {
+			return err
+		}
+		categoryID = uuid.NullUUID{UUID: category.ID, Valid: true}
+	}
+
+	err = s.Db.SetFeedFollowCategory(context.Background(), database.SetFeedFollowCategoryParams{
+		UserID:     follow.UserID,
+		FeedID:     follow.FeedID,
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't set category of %s: %w", follow.FeedName, err)
+	}
+
+	if categoryID.Valid {
+		fmt.Printf("Moved %s to %s\n", follow.FeedName, name)
+	} else {
+		fmt.Printf("Removed %s from its category\n", follow.FeedName)
+	}
+	return nil
+}
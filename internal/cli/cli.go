@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -12,11 +16,13 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/federicoReghini/gator/internal/database"
 	"github.com/federicoReghini/gator/internal/state"
 	"github.com/google/uuid"
+	"golang.org/x/net/html/charset"
 )
 
 type Command struct {
@@ -136,17 +142,72 @@ type RSSFeed struct {
 		Link        string    `xml:"link"`
 		Description string    `xml:"description"`
 		Item        []RSSItem `xml:"item"`
+
+		TTL             string    `xml:"ttl"`
+		UpdatePeriod    string    `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string    `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+		SkipHours       SkipHours `xml:"skipHours"`
+		SkipDays        SkipDays  `xml:"skipDays"`
 	} `xml:"channel"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	GUID        string         `xml:"guid"`
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	PubDate     string         `xml:"pubDate"`
+	Content     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Enclosures  []RSSEnclosure `xml:"enclosure"`
+
+	// Author is usually an email address in RSS, dc:creator holds a name.
+	Author  string `xml:"author"`
+	Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+
+	// Updated is only found in RSS as atom:updated, Atom and JSON Feed
+	// items fill it from their own last modified date.
+	Updated string `xml:"http://www.w3.org/2005/Atom updated"`
+
+	ITunesDuration string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesEpisode  string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesImage    ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
 }
 
-func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+// feedCache holds the validators returned by the last successful fetch of a
+// feed, sent back as If-None-Match / If-Modified-Since on the next one.
+type feedCache struct {
+	ETag         string
+	LastModified string
+}
+
+// fetchResult is what we learnt from fetching a feed. StatusCode is set
+// whenever the server answered, even when fetchFeed returns an error.
+type fetchResult struct {
+	Feed        *RSSFeed
+	NotModified bool
+	Cache       feedCache
+	StatusCode  int
+	RetryAfter  time.Duration
+	// MovedTo is the URL the feed permanently moved to: the target of the last
+	// 301 or 308 followed before any temporary redirect, which only moves the
+	// feed for now. It is empty when the first redirect was temporary.
+	MovedTo         string
+	MovedStatusCode int
+}
+
+var feedClient = http.Client{Timeout: 30 * time.Second}
+
+func fetchFeed(ctx context.Context, feedURL string, cache feedCache) (*fetchResult, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 
 	if err != nil {
@@ -154,23 +215,63 @@ func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 	}
 
 	req.Header.Set("User-Agent", "gator")
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
 
-	client := http.Client{}
+	var movedTo string
+	var movedStatusCode int
+	permanent := true
+	client := feedClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		status := req.Response.StatusCode
+		if permanent && (status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect) {
+			movedTo = req.URL.String()
+			movedStatusCode = status
+		} else {
+			permanent = false
+		}
+		return nil
+	}
 
 	res, err := client.Do(req)
 
 	if err != nil {
-		return nil, errors.New("Something went wrong while prepering request")
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	defer res.Body.Close()
 
+	result := &fetchResult{
+		StatusCode:      res.StatusCode,
+		Cache:           cache,
+		MovedTo:         movedTo,
+		MovedStatusCode: movedStatusCode,
+	}
+
+	if res.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		return result, nil
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		result.RetryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+		return result, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
 	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return result, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-	var feed RSSFeed
-	err = xml.Unmarshal(data, &feed)
+	feed, err := parseFeed(data, res.Header.Get("Content-Type"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
+		return result, err
 	}
 
 	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
@@ -182,48 +283,197 @@ func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 
 	}
 
-	return &feed, nil
+	result.Feed = feed
+	result.Cache = feedCache{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+	return result, nil
 }
 
-func scrapeFeeds(s *state.State) error {
-	feed, err := s.Db.GetNextFeedToFetch(context.Background())
+// parseFeed picks the right decoder for the document: JSON Feed when the
+// Content-Type or the body says so, otherwise the root element decides between
+// <rss> for RSS 2.0, <rdf:RDF> for RSS 1.0 and <feed> in the Atom namespace
+// for Atom 1.0. Every format is mapped onto RSSFeed by its toRSSFeed method,
+// so that scrapeFeed stores the items of all of them in the same way.
+func parseFeed(data []byte, contentType string) (*RSSFeed, error) {
+	if isJSONFeed(contentType, data) {
+		var jsonFeed JSONFeed
+		if err := json.Unmarshal(data, &jsonFeed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON Feed: %w", err)
+		}
+		return jsonFeed.toRSSFeed(), nil
+	}
+
+	root, err := rootElement(data)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
+	}
+
+	switch {
+	case root.Local == "RDF" && root.Space == rdfNamespace:
+		var rdf RDFFeed
+		if err := decodeXML(data, &rdf); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal RDF: %w", err)
+		}
+		return rdf.toRSSFeed(), nil
+	case root.Local == "feed" && root.Space == atomNamespace:
+		var atom AtomFeed
+		if err := decodeXML(data, &atom); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal Atom: %w", err)
+		}
+		return atom.toRSSFeed(), nil
+	default:
+		var feed RSSFeed
+		if err := decodeXML(data, &feed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
+		}
+		return &feed, nil
 	}
-	s.Db.MarkFeedFetched(context.Background(), feed.ID)
+}
 
-	f, err := fetchFeed(context.Background(), feed.Url.String)
+// newXMLDecoder returns a decoder that also reads the documents declaring a
+// non UTF-8 encoding, such as the ISO-8859-1 of many older feeds.
+func newXMLDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder
+}
+
+func decodeXML(data []byte, v any) error {
+	return newXMLDecoder(data).Decode(v)
+}
 
-	for _, item := range f.Channel.Item {
+func rootElement(data []byte) (xml.Name, error) {
+	decoder := newXMLDecoder(data)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.Name{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
+
+func scrapeFeed(s *state.State, feed database.Feed) error {
+	start := time.Now()
+	result, err := fetchFeed(context.Background(), feed.Url.String, feedCache{
+		ETag:         feed.Etag.String,
+		LastModified: feed.LastModified.String,
+	})
+	recordFetch(s, feed, result, err, time.Since(start))
+	if err != nil {
+		return fmt.Errorf("couldn't fetch feed %s: %w", feed.Name, err)
+	}
+
+	if result.MovedTo != "" && result.MovedTo != feed.Url.String {
+		feed, err = moveFeed(s, feed, result.MovedTo, result.MovedStatusCode)
+		if err != nil {
+			return fmt.Errorf("couldn't move feed %s to %s: %w", feed.Name, result.MovedTo, err)
+		}
+	}
+
+	if result.NotModified {
+		log.Printf("feed %s not modified since last fetch", feed.Name)
+		return nil
+	}
+
+	err = s.Db.UpdateFeedCacheHeaders(context.Background(), database.UpdateFeedCacheHeadersParams{
+		ID:           feed.ID,
+		Etag:         sql.NullString{String: result.Cache.ETag, Valid: result.Cache.ETag != ""},
+		LastModified: sql.NullString{String: result.Cache.LastModified, Valid: result.Cache.LastModified != ""},
+	})
+	if err != nil {
+		log.Printf("failed to save cache headers for feed %s: %v", feed.Name, err)
+	}
+
+	cutoff, err := retentionCutoff(s, feed)
+	if err != nil {
+		log.Printf("failed to get retention policy of feed %s: %v", feed.Name, err)
+	}
+
+	for _, item := range result.Feed.Channel.Item {
 		publishedAt, err := parsePublishedDate(item.PubDate)
 		if err != nil {
 			log.Printf("failed to parse published date '%s' for post '%s': %v", item.PubDate, item.Title, err)
 			continue
 		}
+		if publishedAt.Before(cutoff) {
+			continue
+		}
 
-		_, err = s.Db.CreatePost(context.Background(), database.CreatePostParams{
-			ID:          uuid.New(),
-			CreatedAt:   time.Now().UTC(),
-			UpdatedAt:   time.Now().UTC(),
-			Title:       sql.NullString{String: item.Title, Valid: true},
-			Url:         sql.NullString{String: item.Link, Valid: true},
-			Description: sql.NullString{String: item.Description, Valid: true},
-			PublishedAt: publishedAt,
-			FeedID:      uuid.NullUUID{UUID: feed.ID, Valid: true},
+		adoptLegacyPost(s, feed, item)
+
+		post, err := s.Db.CreatePost(context.Background(), database.CreatePostParams{
+			ID:              uuid.New(),
+			CreatedAt:       time.Now().UTC(),
+			UpdatedAt:       time.Now().UTC(),
+			Title:           sql.NullString{String: item.Title, Valid: true},
+			Url:             sql.NullString{String: item.Link, Valid: item.Link != ""},
+			Description:     sql.NullString{String: item.Description, Valid: true},
+			PublishedAt:     publishedAt,
+			FeedID:          uuid.NullUUID{UUID: feed.ID, Valid: true},
+			Content:         sql.NullString{String: item.Content, Valid: item.Content != ""},
+			Guid:            itemGUID(item),
+			ContentHash:     sql.NullString{String: itemHash(item), Valid: true},
+			SourceUpdatedAt: parseOptionalDate(item.Updated),
+			Author:          sql.NullString{String: itemAuthor(item), Valid: itemAuthor(item) != ""},
 		})
-
-		if err != nil {
-			// Check if it's a duplicate URL error and ignore it
-			if isDuplicateURLError(err) {
+		created := err == nil
+
+		// ON CONFLICT DO NOTHING returns no row when the feed already has this item
+		if errors.Is(err, sql.ErrNoRows) {
+			var changed bool
+			post, changed, err = updateChangedPost(s, feed.ID, item)
+			if err != nil {
+				log.Printf("failed to update post '%s': %v", item.Title, err)
+				continue
+			}
+			if !changed {
 				continue
 			}
+		} else if err != nil {
 			log.Printf("failed to create post '%s': %v", item.Title, err)
+			continue
+		}
+
+		saveEnclosures(s, post, item)
+		if created {
+			runIngestRules(s, feed, post)
 		}
 	}
 
 	return nil
 }
 
+func saveEnclosures(s *state.State, post database.Post, item RSSItem) {
+	for _, enclosure := range item.Enclosures {
+		if enclosure.URL == "" {
+			continue
+		}
+
+		length, lengthErr := strconv.ParseInt(enclosure.Length, 10, 64)
+
+		_, err := s.Db.CreatePostEnclosure(context.Background(), database.CreatePostEnclosureParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			PostID:    post.ID,
+			Url:       enclosure.URL,
+			MimeType:  sql.NullString{String: enclosure.Type, Valid: enclosure.Type != ""},
+			Length:    sql.NullInt64{Int64: length, Valid: lengthErr == nil && length > 0},
+			Duration:  sql.NullString{String: item.ITunesDuration, Valid: item.ITunesDuration != ""},
+			Episode:   sql.NullString{String: item.ITunesEpisode, Valid: item.ITunesEpisode != ""},
+			ImageUrl:  sql.NullString{String: item.ITunesImage.Href, Valid: item.ITunesImage.Href != ""},
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("failed to create enclosure '%s' for post '%s': %v", enclosure.URL, item.Title, err)
+		}
+	}
+}
+
 func parsePublishedDate(pubDate string) (time.Time, error) {
 	// Common RSS date formats
 	formats := []string{
@@ -235,6 +485,8 @@ func parsePublishedDate(pubDate string) (time.Time, error) {
 		"2006-01-02 15:04:05",            // Simple format
 		"Mon, 2 Jan 2006 15:04:05 -0700", // RFC1123Z without leading zero
 		"Mon, 2 Jan 2006 15:04:05 MST",   // RFC1123 without leading zero
+		"2006-01-02T15:04Z07:00",         // W3CDTF without seconds (dc:date)
+		"2006-01-02",                     // W3CDTF date only (dc:date)
 	}
 
 	for _, format := range formats {
@@ -246,70 +498,206 @@ func parsePublishedDate(pubDate string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unable to parse date: %s", pubDate)
 }
 
-func isDuplicateURLError(err error) bool {
-	// This checks for common database unique constraint errors
-	errStr := err.Error()
-	return contains(errStr, "UNIQUE constraint failed") ||
-		contains(errStr, "duplicate key") ||
-		contains(errStr, "already exists")
+// itemGUID returns the identity of an item inside its feed: the guid/id
+// published by the feed, the link when there is none, and as a last resort a
+// hash of the title and description.
+func itemGUID(item RSSItem) string {
+	if guid := strings.TrimSpace(item.GUID); guid != "" {
+		return guid
+	}
+	if link := strings.TrimSpace(item.Link); link != "" {
+		return link
+	}
+
+	sum := sha256.Sum256([]byte(item.Title + "\n" + item.Description))
+	return hex.EncodeToString(sum[:])
 }
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr ||
-			(len(s) > len(substr) &&
-				(s[:len(substr)] == substr ||
-					s[len(s)-len(substr):] == substr ||
-					indexOfSubstring(s, substr) >= 0)))
+// legacyGUID returns the guid migration 008 gave to the post of item if it was
+// stored before guids were tracked: its link, used as a placeholder. There is
+// none to look for when the item is keyed on its link anyway.
+func legacyGUID(item RSSItem) (string, bool) {
+	return item.Link, item.Link != "" && item.Link != itemGUID(item)
 }
 
-func indexOfSubstring(s, substr string) int {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
-		}
+// adoptLegacyPost gives the guid of item to the post stored for it before
+// guids were tracked, so that the insert that follows finds it instead of
+// storing the item a second time.
+func adoptLegacyPost(s *state.State, feed database.Feed, item RSSItem) {
+	link, ok := legacyGUID(item)
+	if !ok {
+		return
+	}
+
+	_, err := s.Db.AdoptLegacyPostGuid(context.Background(), database.AdoptLegacyPostGuidParams{
+		Guid:   itemGUID(item),
+		FeedID: uuid.NullUUID{UUID: feed.ID, Valid: true},
+		Url:    sql.NullString{String: link, Valid: true},
+	})
+	if err != nil {
+		log.Printf("failed to match post '%s' stored before guids: %v", item.Title, err)
 	}
-	return -1
 }
 
-func Browse(s *state.State, cmd Command) error {
-	limit := int32(2)
+func itemAuthor(item RSSItem) string {
+	if creator := strings.TrimSpace(item.Creator); creator != "" {
+		return creator
+	}
+	return strings.TrimSpace(item.Author)
+}
 
-	if len(cmd.Args) != 0 {
-		// Parse string to int32
-		parsedLimit, err := strconv.ParseInt(cmd.Args[0], 10, 32)
-		if err != nil {
-			return fmt.Errorf("invalid limit value: %s", cmd.Args[0])
-		}
-		limit = int32(parsedLimit)
+func Browse(s *state.State, cmd Command, user database.User) error {
+	usage := fmt.Errorf("usage: %s [limit] [--limit N] [--feed name|url] [--category name] [--since time] [--until time] [--before post_id] [--all] [--unfiltered] [--content]", cmd.Name)
+	args, flags, err := parseArgs(cmd.Args, "all", "unfiltered", "content")
+	if err != nil || len(args) > 1 {
+		return usage
+	}
+
+	limit := 2
+	if len(args) == 1 {
+		// the limit used to be positional, keep accepting it
+		flags["limit"] = args[0]
+	}
+	if limit, err = intFlag(flags, "limit", limit); err != nil {
+		return err
+	}
+	since, err := timeFlag(flags, "since")
+	if err != nil {
+		return err
+	}
+	until, err := timeFlag(flags, "until")
+	if err != nil {
+		return err
 	}
 
-	posts, err := s.Db.GetPostsForUser(context.Background(), limit)
+	var beforeID uuid.NullUUID
+	if before, ok := flags["before"]; ok {
+		id, err := uuid.Parse(before)
+		if err != nil {
+			return fmt.Errorf("invalid post id: %s", before)
+		}
+		beforeID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	feed, hasFeed := flags["feed"]
+	category, hasCategory := flags["category"]
+	posts, err := s.Db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+		UserID:        uuid.NullUUID{UUID: user.ID, Valid: true},
+		IncludeHidden: flags["unfiltered"] == "true",
+		Feed:          sql.NullString{String: feed, Valid: hasFeed},
+		Category:      sql.NullString{String: category, Valid: hasCategory},
+		Since:         since,
+		Until:         until,
+		BeforeID:      beforeID,
+		IncludeRead:   flags["all"] == "true",
+		MaxPosts:      int32(limit),
+	})
 	if err != nil {
 		return fmt.Errorf("ERROR while getting posts for user: %s", err)
 
 	}
-	fmt.Printf("%+v\n", posts)
+
+	for _, post := range posts {
+		enclosures, err := s.Db.GetEnclosuresForPost(context.Background(), post.Post.ID)
+		if err != nil {
+			return fmt.Errorf("couldn't get enclosures for post: %w", err)
+		}
+		tags, err := s.Db.GetTagsForPost(context.Background(), database.GetTagsForPostParams{
+			UserID: user.ID,
+			PostID: post.Post.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't get tags for post: %w", err)
+		}
+		if post.Highlighted {
+			fmt.Println(">>> Highlighted by a filter")
+		}
+		printPost(post.Post, tags, enclosures, flags["content"] == "true")
+	}
+
+	if len(posts) == limit {
+		fmt.Printf("More posts with --before %s\n", posts[len(posts)-1].Post.ID)
+	}
 	return nil
 
 }
 
+func printPost(post database.Post, tags []string, enclosures []database.PostEnclosure, showContent bool) {
+	fmt.Printf("* ID:            %s\n", post.ID)
+	fmt.Printf("* Title:         %s\n", post.Title.String)
+	fmt.Printf("* URL:           %s\n", post.Url.String)
+	if post.Author.Valid {
+		fmt.Printf("* Author:        %s\n", post.Author.String)
+	}
+	fmt.Printf("* Published:     %s\n", post.PublishedAt.Format(time.RFC1123))
+	if len(tags) > 0 {
+		fmt.Printf("* Tags:          %s\n", strings.Join(tags, ", "))
+	}
+	if post.EditedAt.Valid {
+		fmt.Printf("* Edited:        %s (see post diff %s)\n", post.EditedAt.Time.Format(time.RFC1123), post.ID)
+	}
+	fmt.Printf("* Description:   %s\n", htmlToText(post.Description.String))
+	for _, enclosure := range enclosures {
+		printEnclosure(enclosure)
+	}
+
+	if showContent && post.Content.Valid {
+		fmt.Println()
+		fmt.Println(htmlToText(post.Content.String))
+	}
+	fmt.Println()
+}
+
 func Agg(s *state.State, cmd Command) error {
-	if len(cmd.Args) != 1 {
+	args, flags, err := parseArgs(cmd.Args)
+	if err != nil || len(args) != 1 {
 
-		return fmt.Errorf("usage: %s <time_between_reqs>", cmd.Name)
+		return fmt.Errorf("usage: %s <time_between_reqs> [--workers N] [--batch N] [--per-host N] [--lease duration] [--prune interval]", cmd.Name)
 	}
-	timeBetweenRequests, err := time.ParseDuration(cmd.Args[0])
+	timeBetweenRequests, err := time.ParseDuration(args[0])
 	if err != nil {
 		return fmt.Errorf("invalid duration: %w", err)
 	}
 
-	log.Printf("Collecting feeds every %s...", timeBetweenRequests)
+	workers, err := intFlag(flags, "workers", 1)
+	if err != nil {
+		return err
+	}
+	// by default every worker gets one feed per tick
+	batchSize, err := intFlag(flags, "batch", workers)
+	if err != nil {
+		return err
+	}
+	perHost, err := intFlag(flags, "per-host", 2)
+	if err != nil {
+		return err
+	}
+	limiter := newHostLimiter(perHost)
+
+	lease := 5 * time.Minute
+	if value, ok := flags["lease"]; ok {
+		lease, err = time.ParseDuration(value)
+		if err != nil || lease < time.Second {
+			return fmt.Errorf("invalid value for --lease: %s", value)
+		}
+	}
+
+	if _, ok := flags["prune"]; ok {
+		pruneInterval, err := durationFlag(flags, "prune", 0)
+		if err != nil {
+			return err
+		}
+		go pruneLoop(s, pruneInterval)
+	}
+
+	log.Printf("Collecting %d feeds every %s with %d workers...", batchSize, timeBetweenRequests, workers)
 
 	ticker := time.NewTicker(timeBetweenRequests)
 
 	for ; ; <-ticker.C {
-		scrapeFeeds(s)
+		if err := scrapeBatch(s, batchSize, workers, lease, limiter); err != nil {
+			log.Printf("failed to scrape feeds: %v", err)
+		}
 	}
 }
 
@@ -320,7 +708,10 @@ func AddFeed(s *state.State, cmd Command, user database.User) error {
 	}
 
 	name := cmd.Args[0]
-	url := cmd.Args[1]
+	url, err := resolveFeedURL(context.Background(), cmd.Args[1])
+	if err != nil {
+		return fmt.Errorf("couldn't find a feed at %s: %w", cmd.Args[1], err)
+	}
 
 	feed, err := s.Db.CreateFeed(context.Background(), database.CreateFeedParams{
 		ID:        uuid.New(),
@@ -377,6 +768,17 @@ func Follow(s *state.State, cmd Command, user database.User) error {
 	}
 
 	feed, err := s.Db.GetFeedByUrl(context.Background(), sql.NullString{String: cmd.Args[0], Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		// not a known feed url, it may be the homepage of one
+		feedURL, discoverErr := resolveFeedURL(context.Background(), cmd.Args[0])
+		if discoverErr != nil {
+			return fmt.Errorf("couldn't get feed: %w", discoverErr)
+		}
+		feed, err = s.Db.GetFeedByUrl(context.Background(), sql.NullString{String: feedURL, Valid: true})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s is not added yet, use addfeed first", feedURL)
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("couldn't get feed: %w", err)
 	}
@@ -410,11 +812,38 @@ func Following(s *state.State, cmd Command, user database.User) error {
 		return nil
 	}
 
+	categories, err := s.Db.GetCategoriesForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get categories: %w", err)
+	}
+
+	// follows are ordered by category, uncategorized ones first
 	fmt.Printf("Feed follows for user %s:\n", user.Name)
+	var current sql.NullString
 	for _, ff := range feedFollows {
+		if ff.CategoryName != current {
+			fmt.Printf("%s/\n", ff.CategoryName.String)
+			current = ff.CategoryName
+		}
+		if current.Valid {
+			fmt.Print("  ")
+		}
 		fmt.Printf("* %s\n", ff.FeedName)
 	}
 
+	for _, category := range categories {
+		empty := true
+		for _, ff := range feedFollows {
+			if ff.CategoryID.UUID == category.ID {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			fmt.Printf("%s/ (empty)\n", category.Name)
+		}
+	}
+
 	return nil
 }
 
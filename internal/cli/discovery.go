@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// commonFeedPaths are tried when a page doesn't advertise any feed.
+var commonFeedPaths = []string{"/feed", "/rss.xml", "/atom.xml", "/feed.xml", "/index.xml", "/feed.json"}
+
+var feedLinkTypes = map[string]string{
+	"application/rss+xml":   "RSS",
+	"application/atom+xml":  "Atom",
+	"application/feed+json": "JSON Feed",
+}
+
+var (
+	linkTagRegexp   = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	attributeRegexp = regexp.MustCompile(`(?s)([\w-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+type feedCandidate struct {
+	URL   string
+	Title string
+	Type  string
+}
+
+// resolveFeedURL returns rawURL when it points to a feed. Otherwise rawURL is
+// treated as a web page and the feeds it advertises (or, failing that, the
+// ones found at common paths) are offered, asking the user to pick one when
+// there is more than one. When rawURL can't be fetched it is returned as
+// given: it may be a feed that is down for now.
+func resolveFeedURL(ctx context.Context, rawURL string) (string, error) {
+	data, err := fetchPage(ctx, rawURL)
+	if err != nil {
+		fmt.Printf("Couldn't check %s (%v), using it as given\n", rawURL, err)
+		return rawURL, nil
+	}
+	if isFeedDocument(data) {
+		return rawURL, nil
+	}
+
+	candidates := feedLinks(data, rawURL)
+	if len(candidates) == 0 {
+		candidates = probeCommonPaths(ctx, rawURL)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no feed found at %s", rawURL)
+	case 1:
+		fmt.Printf("Found feed %s\n", candidates[0].URL)
+		return candidates[0].URL, nil
+	default:
+		return chooseFeed(candidates)
+	}
+}
+
+func fetchPage(ctx context.Context, pageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %s: %w", pageURL, err)
+	}
+	req.Header.Set("User-Agent", "gator")
+
+	res, err := feedClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return data, nil
+}
+
+// isFeedDocument reports whether data is a feed in one of the formats that
+// parseFeed understands, as opposed to a web page.
+func isFeedDocument(data []byte) bool {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var feed JSONFeed
+		return json.Unmarshal(data, &feed) == nil && strings.Contains(feed.Version, "jsonfeed.org")
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return false
+	}
+	return root.Local == "rss" ||
+		(root.Local == "RDF" && root.Space == rdfNamespace) ||
+		(root.Local == "feed" && root.Space == atomNamespace)
+}
+
+// feedLinks returns the <link rel="alternate"> feeds of an html page, with
+// their href resolved against pageURL.
+func feedLinks(page []byte, pageURL string) []feedCandidate {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []feedCandidate
+	seen := map[string]bool{}
+	for _, tag := range linkTagRegexp.FindAll(page, -1) {
+		attrs := map[string]string{}
+		for _, match := range attributeRegexp.FindAllSubmatch(tag, -1) {
+			value := strings.Trim(string(match[2]), `"'`)
+			attrs[strings.ToLower(string(match[1]))] = html.UnescapeString(value)
+		}
+
+		rels := strings.Fields(strings.ToLower(attrs["rel"]))
+		feedType, ok := feedLinkTypes[strings.ToLower(strings.TrimSpace(attrs["type"]))]
+		if !ok || !slices.Contains(rels, "alternate") || attrs["href"] == "" {
+			continue
+		}
+
+		href, err := base.Parse(strings.TrimSpace(attrs["href"]))
+		if err != nil || seen[href.String()] {
+			continue
+		}
+		seen[href.String()] = true
+
+		candidates = append(candidates, feedCandidate{URL: href.String(), Title: attrs["title"], Type: feedType})
+	}
+
+	return candidates
+}
+
+func probeCommonPaths(ctx context.Context, pageURL string) []feedCandidate {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []feedCandidate
+	for _, path := range commonFeedPaths {
+		candidate := base.ResolveReference(&url.URL{Path: path}).String()
+		data, err := fetchPage(ctx, candidate)
+		if err != nil || !isFeedDocument(data) {
+			continue
+		}
+		candidates = append(candidates, feedCandidate{URL: candidate})
+	}
+
+	return candidates
+}
+
+func chooseFeed(candidates []feedCandidate) (string, error) {
+	fmt.Println("Several feeds were found:")
+	for i, candidate := range candidates {
+		fmt.Printf("%d. %s", i+1, candidate.URL)
+		if candidate.Title != "" || candidate.Type != "" {
+			fmt.Printf(" (%s)", strings.TrimSpace(candidate.Title+" "+candidate.Type))
+		}
+		fmt.Println()
+	}
+	fmt.Print("Pick one: ")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return "", fmt.Errorf("invalid choice: %s", strings.TrimSpace(line))
+	}
+
+	return candidates[choice-1].URL, nil
+}
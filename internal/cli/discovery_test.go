@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestFeedLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want []feedCandidate
+	}{
+		{
+			name: "double quotes",
+			page: `<link rel="alternate" type="application/rss+xml" title="Posts" href="/posts.xml">`,
+			want: []feedCandidate{{URL: "https://example.com/posts.xml", Title: "Posts", Type: "RSS"}},
+		},
+		{
+			name: "single quotes and unquoted values",
+			page: `<LINK REL='alternate' TYPE=application/atom+xml HREF=atom.xml title='A &amp; B'/>`,
+			want: []feedCandidate{{URL: "https://example.com/blog/atom.xml", Title: "A & B", Type: "Atom"}},
+		},
+		{
+			name: "multi-valued rel",
+			page: `<link rel="home alternate" type="application/feed+json" href="https://other.example/feed.json">`,
+			want: []feedCandidate{{URL: "https://other.example/feed.json", Type: "JSON Feed"}},
+		},
+		{
+			name: "duplicates",
+			page: `<link rel="alternate" type="application/rss+xml" href="/posts.xml">
+<link rel="alternate" type="application/rss+xml" href="https://example.com/posts.xml">`,
+			want: []feedCandidate{{URL: "https://example.com/posts.xml", Type: "RSS"}},
+		},
+		{
+			name: "not feeds",
+			page: `<link rel="stylesheet" type="text/css" href="/style.css">
+<link rel="alternate" hreflang="fr" href="/fr/">
+<link rel="alternate" type="application/rss+xml">`,
+		},
+	}
+	for _, tt := range tests {
+		got := feedLinks([]byte(tt.page), "https://example.com/blog/")
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: feedLinks = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveFeedURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/posts.xml":
+			w.Write([]byte(`<rss version="2.0"><channel><title>Feed</title></channel></rss>`))
+		case "/with-feed":
+			w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/posts.xml"></head></html>`))
+		case "/no-feed":
+			w.Write([]byte(`<html><head><title>Home</title></head></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	for path, want := range map[string]string{
+		"/posts.xml": server.URL + "/posts.xml",
+		"/with-feed": server.URL + "/posts.xml",
+	} {
+		got, err := resolveFeedURL(ctx, server.URL+path)
+		if err != nil || got != want {
+			t.Errorf("resolveFeedURL(%s) = %q, %v, want %q", path, got, err, want)
+		}
+	}
+
+	if got, err := resolveFeedURL(ctx, server.URL+"/no-feed"); err == nil {
+		t.Errorf("resolveFeedURL of a page without feeds = %q, want an error", got)
+	}
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	if got, err := resolveFeedURL(ctx, down.URL+"/posts.xml"); err != nil || got != down.URL+"/posts.xml" {
+		t.Errorf("resolveFeedURL of an unreachable url = %q, %v, want it as given", got, err)
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+func printEnclosure(enclosure database.PostEnclosure) {
+	details := []string{}
+	if enclosure.MimeType.Valid {
+		details = append(details, enclosure.MimeType.String)
+	}
+	if enclosure.Length.Valid {
+		details = append(details, fmt.Sprintf("%.1f MB", float64(enclosure.Length.Int64)/(1024*1024)))
+	}
+	if enclosure.Duration.Valid {
+		details = append(details, enclosure.Duration.String)
+	}
+	if enclosure.Episode.Valid {
+		details = append(details, "episode "+enclosure.Episode.String)
+	}
+
+	fmt.Printf("* Enclosure:     %s", enclosure.Url)
+	if len(details) > 0 {
+		fmt.Printf(" (%s)", strings.Join(details, ", "))
+	}
+	fmt.Println()
+	if enclosure.ImageUrl.Valid {
+		fmt.Printf("* Image:         %s\n", enclosure.ImageUrl.String)
+	}
+}
+
+func Download(s *state.State, cmd Command) error {
+	if len(cmd.Args) != 1 {
+		return fmt.Errorf("usage: %s <post_id>", cmd.Name)
+	}
+
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %s", cmd.Args[0])
+	}
+
+	enclosures, err := s.Db.GetEnclosuresForPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("couldn't get enclosures for post: %w", err)
+	}
+	if len(enclosures) == 0 {
+		return errors.New("this post has no enclosures to download")
+	}
+
+	dir, err := s.Cfg.GetDownloadDir()
+	if err != nil {
+		return fmt.Errorf("couldn't find download directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("couldn't create download directory: %w", err)
+	}
+
+	// enclosure file names are often reused across episodes (audio.mp3,
+	// download.mp3...), so each post gets its own directory
+	postDir := filepath.Join(dir, postID.String())
+	if err := os.MkdirAll(postDir, 0755); err != nil {
+		return fmt.Errorf("couldn't create download directory: %w", err)
+	}
+
+	for _, enclosure := range enclosures {
+		dest := filepath.Join(postDir, enclosureFileName(enclosure))
+		if _, err := os.Stat(dest); err == nil {
+			fmt.Printf("Already downloaded %s\n", dest)
+			continue
+		}
+		if err := downloadFile(context.Background(), enclosure.Url, dest); err != nil {
+			return fmt.Errorf("couldn't download %s: %w", enclosure.Url, err)
+		}
+		fmt.Printf("Saved %s\n", dest)
+	}
+
+	return nil
+}
+
+func enclosureFileName(enclosure database.PostEnclosure) string {
+	if u, err := url.Parse(enclosure.Url); err == nil {
+		name := path.Base(u.Path)
+		if name != "" && name != "." && name != "/" {
+			return name
+		}
+	}
+	return enclosure.ID.String()
+}
+
+// downloadFile saves the resource at fileURL into dest. The data is written
+// to dest.part, renamed to dest once complete. When a .part file from a
+// previous run is found the download resumes from where it stopped using a
+// Range request, and starts over if the server doesn't support it.
+func downloadFile(ctx context.Context, fileURL, dest string) error {
+	partial := dest + ".part"
+
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "gator")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(res.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return restartDownload(ctx, fileURL, dest, partial, res)
+		}
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file doesn't match the resource anymore
+		return restartDownload(ctx, fileURL, dest, partial, res)
+	default:
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	file, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, res.Body); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(partial, dest)
+}
+
+// restartDownload drops the partial file that couldn't be resumed and
+// downloads the whole resource again.
+func restartDownload(ctx context.Context, fileURL, dest, partial string, res *http.Response) error {
+	res.Body.Close()
+	if err := os.Remove(partial); err != nil {
+		return err
+	}
+	return downloadFile(ctx, fileURL, dest)
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+var filterFields = []string{"title", "description", "author", "any"}
+
+// Filter manages the rules hiding or highlighting posts in browse. They are
+// applied by GetPostsForUser, so they also affect the posts already stored.
+func Filter(s *state.State, cmd Command, user database.User) error {
+	usage := fmt.Errorf("usage: %s add <hide|highlight> <keyword|regex> [--regex] [--field title|description|author|any] [--feed name|url] | list | rm <filter_id>", cmd.Name)
+	if len(cmd.Args) == 0 {
+		return usage
+	}
+
+	switch cmd.Args[0] {
+	case "add":
+		args, flags, err := parseArgs(cmd.Args[1:], "regex")
+		if err != nil || len(args) != 2 {
+			return usage
+		}
+		return addFilter(s, user, args[0], args[1], flags)
+
+	case "list":
+		if len(cmd.Args) != 1 {
+			return usage
+		}
+		return listFilters(s, user)
+
+	case "rm":
+		if len(cmd.Args) != 2 {
+			return usage
+		}
+		filterID, err := uuid.Parse(cmd.Args[1])
+		if err != nil {
+			return fmt.Errorf("invalid filter id: %s", cmd.Args[1])
+		}
+
+		deleted, err := s.Db.DeletePostFilter(context.Background(), database.DeletePostFilterParams{
+			ID:     filterID,
+			UserID: user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't delete filter: %w", err)
+		}
+		if deleted == 0 {
+			return fmt.Errorf("no filter with id %s", filterID)
+		}
+		fmt.Printf("Deleted filter %s\n", filterID)
+		return nil
+
+	default:
+		return usage
+	}
+}
+
+func addFilter(s *state.State, user database.User, action, pattern string, flags map[string]string) error {
+	if action != "hide" && action != "highlight" {
+		return fmt.Errorf("unknown filter action %s, expected hide or highlight", action)
+	}
+
+	field := "any"
+	if value, ok := flags["field"]; ok {
+		field = value
+	}
+	if !slices.Contains(filterFields, field) {
+		return fmt.Errorf("unknown field %s, expected one of %s", field, strings.Join(filterFields, ", "))
+	}
+
+	isRegex := flags["regex"] == "true"
+	if isRegex {
+		// filters are matched by postgres, so its regex flavor decides
+		if err := s.Db.CheckFilterRegex(context.Background(), pattern); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+
+	var feedID uuid.NullUUID
+	if feedFlag, ok := flags["feed"]; ok {
+		feed, err := findFollowedFeed(s, user, feedFlag)
+		if err != nil {
+			return err
+		}
+		feedID = feed.FeedID
+	}
+
+	filter, err := s.Db.CreatePostFilter(context.Background(), database.CreatePostFilterParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		FeedID:    feedID,
+		Field:     field,
+		Pattern:   pattern,
+		IsRegex:   isRegex,
+		Action:    action,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't create filter: %w", err)
+	}
+
+	fmt.Printf("Created filter %s\n", filter.ID)
+	return nil
+}
+
+func listFilters(s *state.State, user database.User) error {
+	filters, err := s.Db.GetPostFiltersForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get filters: %w", err)
+	}
+
+	if len(filters) == 0 {
+		fmt.Println("No filters.")
+		return nil
+	}
+	for _, filter := range filters {
+		kind := "keyword"
+		if filter.IsRegex {
+			kind = "regex"
+		}
+		scope := "all feeds"
+		if filter.FeedName.Valid {
+			scope = filter.FeedName.String
+		}
+		fmt.Printf("* %s: %s posts whose %s matches %s %q (%s)\n", filter.ID, filter.Action, filter.Field, kind, filter.Pattern, scope)
+	}
+	return nil
+}
+
+// findFollowedFeed returns the follow of the feed with the given name or url.
+func findFollowedFeed(s *state.State, user database.User, feed string) (database.GetFeedFollowsForUserRow, error) {
+	feedFollows, err := s.Db.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return database.GetFeedFollowsForUserRow{}, fmt.Errorf("couldn't get feed follows: %w", err)
+	}
+
+	for _, ff := range feedFollows {
+		if ff.FeedUrl.String == feed || ff.FeedName == feed {
+			return ff, nil
+		}
+	}
+	return database.GetFeedFollowsForUserRow{}, fmt.Errorf("you don't follow %s", feed)
+}
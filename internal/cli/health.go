@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+// healthWindow is how far back health looks at fetches, prune deletes the
+// fetch log entries older than that.
+const healthWindow = 7 * 24 * time.Hour
+
+// recordFetch writes the outcome of a fetch to feed_fetch_log and updates the
+// consecutive failures counter of the feed.
+func recordFetch(s *state.State, feed database.Feed, result *fetchResult, fetchErr error, duration time.Duration) {
+	params := database.CreateFeedFetchLogParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		FeedID:     feed.ID,
+		DurationMs: int32(duration.Milliseconds()),
+	}
+	if result != nil {
+		params.StatusCode = sql.NullInt32{Int32: int32(result.StatusCode), Valid: true}
+		if result.Feed != nil {
+			params.ItemCount = int32(len(result.Feed.Channel.Item))
+		}
+	}
+	if fetchErr != nil {
+		params.Error = sql.NullString{String: fetchErr.Error(), Valid: true}
+	}
+
+	if _, err := s.Db.CreateFeedFetchLog(context.Background(), params); err != nil {
+		log.Printf("failed to log fetch of feed %s: %v", feed.Name, err)
+	}
+
+	if fetchErr == nil {
+		// a 304 has no body to learn from, so the last interval is kept
+		interval := defaultFetchInterval
+		if feed.FetchIntervalSeconds.Valid {
+			interval = time.Duration(feed.FetchIntervalSeconds.Int32) * time.Second
+		}
+		next := time.Now().Add(interval)
+		if result.Feed != nil {
+			interval = fetchInterval(result.Feed)
+			next = nextFetchTime(result.Feed, time.Now(), interval)
+		}
+
+		err := s.Db.MarkFeedFetchSucceeded(context.Background(), database.MarkFeedFetchSucceededParams{
+			ID:                   feed.ID,
+			FetchIntervalSeconds: sql.NullInt32{Int32: int32(interval.Seconds()), Valid: true},
+			NextFetchInSeconds:   int32(time.Until(next).Seconds()),
+		})
+		if err != nil {
+			log.Printf("failed to mark feed %s fetched: %v", feed.Name, err)
+		}
+		return
+	}
+
+	var retryAfter time.Duration
+	if result != nil {
+		retryAfter = result.RetryAfter
+	}
+	delay := backoff(feed.ConsecutiveFailures+1, retryAfter)
+
+	err := s.Db.MarkFeedFetchFailed(context.Background(), database.MarkFeedFetchFailedParams{
+		ID:             feed.ID,
+		RetryInSeconds: int32(delay.Seconds()),
+	})
+	if err != nil {
+		log.Printf("failed to mark feed %s fetched: %v", feed.Name, err)
+	}
+
+	if result != nil && result.StatusCode == http.StatusGone {
+		log.Printf("feed %s is gone, disabling it (use enable to fetch it again)", feed.Name)
+		if err := s.Db.DisableFeed(context.Background(), feed.ID); err != nil {
+			log.Printf("failed to disable feed %s: %v", feed.Name, err)
+		}
+	}
+}
+
+func Health(s *state.State, cmd Command) error {
+	args, flags, err := parseArgs(cmd.Args)
+	if err != nil || len(args) != 0 {
+		return fmt.Errorf("usage: %s [--failures N] [--slow duration] [--stale duration]", cmd.Name)
+	}
+
+	failures, err := intFlag(flags, "failures", 3)
+	if err != nil {
+		return err
+	}
+	slow, err := durationFlag(flags, "slow", 5*time.Second)
+	if err != nil {
+		return err
+	}
+	stale, err := durationFlag(flags, "stale", 90*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	feeds, err := s.Db.GetFeedHealth(context.Background(), time.Now().UTC().Add(-healthWindow))
+	if err != nil {
+		return fmt.Errorf("couldn't get feed health: %w", err)
+	}
+
+	var disabled, broken, slowFeeds, staleFeeds []database.GetFeedHealthRow
+	for _, feed := range feeds {
+		if feed.DisabledAt.Valid {
+			disabled = append(disabled, feed)
+			continue
+		}
+		if int(feed.ConsecutiveFailures) >= failures {
+			broken = append(broken, feed)
+		}
+		if time.Duration(feed.AvgDurationMs)*time.Millisecond >= slow {
+			slowFeeds = append(slowFeeds, feed)
+		}
+		if time.Since(feed.LatestPostAt) >= stale {
+			staleFeeds = append(staleFeeds, feed)
+		}
+	}
+
+	changes, err := s.Db.GetFeedUrlChangesSince(context.Background(), time.Now().UTC().Add(-30*24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("couldn't get feed url changes: %w", err)
+	}
+
+	fmt.Println("Moved feeds (last 30 days):")
+	for _, change := range changes {
+		fmt.Printf("* %s: %s -> %s (%d on %s)", change.FeedName, change.OldUrl, change.NewUrl, change.StatusCode, change.CreatedAt.Format(time.DateOnly))
+		if change.Merged {
+			fmt.Print(", merged with the feed already there")
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("\nDisabled feeds:")
+	for _, feed := range disabled {
+		fmt.Printf("* %s (%s): disabled on %s, last error: %s\n", feed.Name, feed.Url.String, feed.DisabledAt.Time.Format(time.DateOnly), feed.LastError)
+	}
+
+	fmt.Printf("\nBroken feeds (%d or more failures in a row):\n", failures)
+	for _, feed := range broken {
+		fmt.Printf("* %s (%s): %d failures, last error: %s", feed.Name, feed.Url.String, feed.ConsecutiveFailures, feed.LastError)
+		if feed.NextFetchAt.Valid {
+			fmt.Printf(", next try at %s", feed.NextFetchAt.Time.Format(time.DateTime))
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\nSlow feeds (%s or more on average this week):\n", slow)
+	for _, feed := range slowFeeds {
+		fmt.Printf("* %s (%s): %s\n", feed.Name, feed.Url.String, time.Duration(feed.AvgDurationMs)*time.Millisecond)
+	}
+
+	fmt.Printf("\nStale feeds (nothing new for %s):\n", stale)
+	for _, feed := range staleFeeds {
+		fmt.Printf("* %s (%s): last post on %s\n", feed.Name, feed.Url.String, feed.LatestPostAt.Format(time.DateOnly))
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// JSONFeed covers both version 1.0 and 1.1 of https://jsonfeed.org.
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            JSONFeedID `json:"id"`
+	URL           string     `json:"url"`
+	ExternalURL   string     `json:"external_url"`
+	Title         string     `json:"title"`
+	ContentHTML   string     `json:"content_html"`
+	ContentText   string     `json:"content_text"`
+	Summary       string     `json:"summary"`
+	DatePublished string     `json:"date_published"`
+	DateModified  string     `json:"date_modified"`
+	Image         string     `json:"image"`
+
+	// Author is from version 1.0, replaced by Authors in 1.1.
+	Author  JSONFeedAuthor   `json:"author"`
+	Authors []JSONFeedAuthor `json:"authors"`
+
+	Attachments []JSONFeedAttachment `json:"attachments"`
+}
+
+// JSONFeedID is the id of an item. The spec says it is a string, but asks
+// readers to accept a number too, as some feeds publish numeric ids.
+type JSONFeedID string
+
+func (id *JSONFeedID) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		return json.Unmarshal(data, (*string)(id))
+	}
+	if string(data) == "null" {
+		return nil
+	}
+
+	// json.Number keeps the number as written, so large ids aren't rounded
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*id = JSONFeedID(number)
+	return nil
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type JSONFeedAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+// isJSONFeed reports whether the response should go through the JSON Feed
+// decoder, either because the server says so or because the body looks like
+// a JSON object.
+func isJSONFeed(contentType string, data []byte) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.Contains(contentType, "application/feed+json") || strings.Contains(contentType, "application/json") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+func (f *JSONFeed) toRSSFeed() *RSSFeed {
+	var feed RSSFeed
+	feed.Channel.Title = f.Title
+	feed.Channel.Link = f.HomePageURL
+	feed.Channel.Description = f.Description
+
+	for _, item := range f.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		description := item.Summary
+		if description == "" {
+			description = item.ContentHTML
+		}
+		if description == "" {
+			description = item.ContentText
+		}
+
+		content := item.ContentHTML
+		if content == "" {
+			content = item.ContentText
+		}
+
+		pubDate := item.DatePublished
+		if pubDate == "" {
+			pubDate = item.DateModified
+		}
+
+		var authors []string
+		for _, author := range append([]JSONFeedAuthor{item.Author}, item.Authors...) {
+			if name := strings.TrimSpace(author.Name); name != "" {
+				authors = append(authors, name)
+			}
+		}
+
+		rssItem := RSSItem{
+			GUID:        string(item.ID),
+			Title:       item.Title,
+			Link:        link,
+			Description: description,
+			PubDate:     pubDate,
+			Updated:     item.DateModified,
+			Content:     content,
+			ITunesImage: ITunesImage{Href: item.Image},
+			Creator:     strings.Join(authors, ", "),
+		}
+
+		for _, attachment := range item.Attachments {
+			enclosure := RSSEnclosure{URL: attachment.URL, Type: attachment.MimeType}
+			if attachment.SizeInBytes > 0 {
+				enclosure.Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+			if attachment.DurationInSeconds > 0 && rssItem.ITunesDuration == "" {
+				rssItem.ITunesDuration = strconv.Itoa(int(attachment.DurationInSeconds))
+			}
+			rssItem.Enclosures = append(rssItem.Enclosures, enclosure)
+		}
+
+		feed.Channel.Item = append(feed.Channel.Item, rssItem)
+	}
+
+	return &feed
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/migrate"
+	"github.com/federicoReghini/gator/internal/state"
+)
+
+func Migrate(s *state.State, cmd Command) error {
+	if len(cmd.Args) != 1 {
+		return fmt.Errorf("usage: %s up|down|status", cmd.Name)
+	}
+
+	switch cmd.Args[0] {
+	case "up":
+		done, err := migrate.Up(context.Background(), s.Conn)
+		for _, migration := range done {
+			fmt.Printf("Applied %s\n", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("The database is up to date.")
+		}
+		return nil
+
+	case "down":
+		migration, ok, err := migrate.Down(context.Background(), s.Conn)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("No migration to roll back.")
+			return nil
+		}
+		fmt.Printf("Rolled back %s\n", migration.Name)
+		return nil
+
+	case "status":
+		statuses, err := migrate.Status(context.Background(), s.Conn)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt.Valid {
+				appliedAt = "applied " + status.AppliedAt.Time.Format(time.RFC1123)
+			}
+			fmt.Printf("* %-30s %s\n", status.Name, appliedAt)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("usage: %s up|down|status", cmd.Name)
+	}
+}
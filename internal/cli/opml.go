@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type OPML struct {
+	XMLName xml.Name    `xml:"opml"`
+	Version string      `xml:"version,attr"`
+	Head    OPMLHead    `xml:"head"`
+	Body    []OPMLEntry `xml:"body>outline"`
+}
+
+type OPMLHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type OPMLEntry struct {
+	Text     string      `xml:"text,attr"`
+	Title    string      `xml:"title,attr,omitempty"`
+	Type     string      `xml:"type,attr,omitempty"`
+	XMLURL   string      `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string      `xml:"htmlUrl,attr,omitempty"`
+	Outlines []OPMLEntry `xml:"outline"`
+}
+
+type opmlFeed struct {
+	Name     string
+	URL      string
+	Category string
+}
+
+// flattenOPML returns every outline with an xmlUrl, using the closest parent
+// outline without one as its category.
+func flattenOPML(entries []OPMLEntry, category string) []opmlFeed {
+	var feeds []opmlFeed
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Title)
+		if name == "" {
+			name = strings.TrimSpace(entry.Text)
+		}
+
+		if entry.XMLURL == "" {
+			feeds = append(feeds, flattenOPML(entry.Outlines, name)...)
+			continue
+		}
+
+		if name == "" {
+			name = entry.XMLURL
+		}
+		feeds = append(feeds, opmlFeed{Name: name, URL: strings.TrimSpace(entry.XMLURL), Category: category})
+		feeds = append(feeds, flattenOPML(entry.Outlines, category)...)
+	}
+	return feeds
+}
+
+func Import(s *state.State, cmd Command, user database.User) error {
+	if len(cmd.Args) != 1 {
+		return fmt.Errorf("usage: %s <file.opml>", cmd.Name)
+	}
+
+	data, err := os.ReadFile(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", cmd.Args[0], err)
+	}
+
+	var opml OPML
+	if err := xml.Unmarshal(data, &opml); err != nil {
+		return fmt.Errorf("failed to unmarshal OPML: %w", err)
+	}
+
+	feedFollows, err := s.Db.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("couldn't get feed follows: %w", err)
+	}
+	following := map[uuid.UUID]bool{}
+	for _, ff := range feedFollows {
+		following[ff.FeedID.UUID] = true
+	}
+
+	created, followed := 0, 0
+	for _, entry := range flattenOPML(opml.Body, "") {
+		feed, err := s.Db.GetFeedByUrl(context.Background(), sql.NullString{String: entry.URL, Valid: true})
+		if errors.Is(err, sql.ErrNoRows) {
+			feed, err = createFeedWithUniqueName(s, entry.Name, entry.URL, user)
+			if err == nil {
+				created++
+			}
+		}
+		if err != nil {
+			fmt.Printf("Skipping %s: %v\n", entry.URL, err)
+			continue
+		}
+
+		var categoryID uuid.NullUUID
+		if entry.Category != "" {
+			category, err := s.Db.UpsertCategory(context.Background(), database.UpsertCategoryParams{
+				ID:        uuid.New(),
+				CreatedAt: time.Now().UTC(),
+				UpdatedAt: time.Now().UTC(),
+				UserID:    user.ID,
+				Name:      entry.Category,
+			})
+			if err != nil {
+				return fmt.Errorf("couldn't create category %s: %w", entry.Category, err)
+			}
+			categoryID = uuid.NullUUID{UUID: category.ID, Valid: true}
+		}
+
+		if following[feed.ID] {
+			if categoryID.Valid {
+				err = s.Db.SetFeedFollowCategory(context.Background(), database.SetFeedFollowCategoryParams{
+					UserID:     uuid.NullUUID{UUID: user.ID, Valid: true},
+					FeedID:     uuid.NullUUID{UUID: feed.ID, Valid: true},
+					CategoryID: categoryID,
+				})
+				if err != nil {
+					return fmt.Errorf("couldn't set category of %s: %w", feed.Name, err)
+				}
+			}
+			continue
+		}
+
+		_, err = s.Db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+			ID:         uuid.New(),
+			CreatedAt:  time.Now().UTC(),
+			UpdatedAt:  time.Now().UTC(),
+			UserID:     uuid.NullUUID{UUID: user.ID, Valid: true},
+			FeedID:     uuid.NullUUID{UUID: feed.ID, Valid: true},
+			CategoryID: categoryID,
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't create feed follow: %w", err)
+		}
+		following[feed.ID] = true
+		followed++
+	}
+
+	fmt.Printf("Imported %s: %d new feeds, %d new follows.\n", cmd.Args[0], created, followed)
+	return nil
+}
+
+// createFeedWithUniqueName creates the feed, adding a numeric suffix to its
+// name when another feed already uses it since feed names are unique.
+func createFeedWithUniqueName(s *state.State, name, url string, user database.User) (database.Feed, error) {
+	candidate := name
+	for i := 2; ; i++ {
+		feed, err := s.Db.CreateFeed(context.Background(), database.CreateFeedParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      candidate,
+			Url:       sql.NullString{String: url, Valid: true},
+			UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		})
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "feeds_name_key" && i < 100 {
+			candidate = fmt.Sprintf("%s (%d)", name, i)
+			continue
+		}
+		return feed, err
+	}
+}
+
+func Export(s *state.State, cmd Command, user database.User) error {
+	if len(cmd.Args) > 1 {
+		return fmt.Errorf("usage: %s [file]", cmd.Name)
+	}
+
+	feedFollows, err := s.Db.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("couldn't get feed follows: %w", err)
+	}
+
+	opml := OPML{
+		Version: "2.0",
+		Head: OPMLHead{
+			Title:       fmt.Sprintf("%s's gator subscriptions", user.Name),
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	// follows are ordered by category, so each folder is a contiguous run
+	for _, ff := range feedFollows {
+		entry := OPMLEntry{
+			Text:   ff.FeedName,
+			Title:  ff.FeedName,
+			Type:   "rss",
+			XMLURL: ff.FeedUrl.String,
+		}
+
+		if !ff.CategoryName.Valid {
+			opml.Body = append(opml.Body, entry)
+			continue
+		}
+
+		last := len(opml.Body) - 1
+		if last < 0 || opml.Body[last].XMLURL != "" || opml.Body[last].Text != ff.CategoryName.String {
+			opml.Body = append(opml.Body, OPMLEntry{Text: ff.CategoryName.String, Title: ff.CategoryName.String})
+			last++
+		}
+		opml.Body[last].Outlines = append(opml.Body[last].Outlines, entry)
+	}
+
+	data, err := xml.MarshalIndent(opml, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal OPML: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+
+	if len(cmd.Args) == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(cmd.Args[0], data, 0644); err != nil {
+		return fmt.Errorf("couldn't write %s: %w", cmd.Args[0], err)
+	}
+	fmt.Printf("Exported %d feeds to %s\n", len(feedFollows), cmd.Args[0])
+	return nil
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFlattenOPML(t *testing.T) {
+	entries := []OPMLEntry{
+		{Text: "Loose", XMLURL: "https://example.com/loose.xml"},
+		{Text: "Tech", Outlines: []OPMLEntry{
+			{Text: "text", Title: "Go Blog", XMLURL: " https://go.dev/blog/feed.atom "},
+			{Text: "Nested", Outlines: []OPMLEntry{
+				{XMLURL: "https://example.com/nameless.xml"},
+			}},
+		}},
+	}
+	want := []opmlFeed{
+		{Name: "Loose", URL: "https://example.com/loose.xml"},
+		{Name: "Go Blog", URL: "https://go.dev/blog/feed.atom", Category: "Tech"},
+		{Name: "https://example.com/nameless.xml", URL: "https://example.com/nameless.xml", Category: "Nested"},
+	}
+
+	if got := flattenOPML(entries, ""); !slices.Equal(got, want) {
+		t.Errorf("flattenOPML = %+v, want %+v", got, want)
+	}
+}
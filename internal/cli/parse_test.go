@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        string
+		title       string
+		items       []RSSItem
+	}{
+		{
+			name: "rss",
+			data: `<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+  <title>RSS Feed</title>
+  <item>
+    <guid>rss-1</guid>
+    <title>First &amp; best</title>
+    <link>https://example.com/1</link>
+    <description>Summary</description>
+    <content:encoded><![CDATA[<p>Body</p>]]></content:encoded>
+    <pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+    <dc:creator>Jane</dc:creator>
+    <enclosure url="https://example.com/1.mp3" length="123" type="audio/mpeg"/>
+  </item>
+</channel>
+</rss>`,
+			title: "RSS Feed",
+			items: []RSSItem{{
+				GUID:        "rss-1",
+				Title:       "First & best",
+				Link:        "https://example.com/1",
+				Description: "Summary",
+				Content:     "<p>Body</p>",
+				PubDate:     "Mon, 02 Jan 2006 15:04:05 +0000",
+				Creator:     "Jane",
+				Enclosures:  []RSSEnclosure{{URL: "https://example.com/1.mp3", Length: "123", Type: "audio/mpeg"}},
+			}},
+		},
+		{
+			name: "rss in ISO-8859-1",
+			data: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+				"<rss version=\"2.0\"><channel><title>Caf\xe9</title>" +
+				"<item><guid>latin-1</guid><title>Cr\xe8me</title></item></channel></rss>",
+			title: "Café",
+			items: []RSSItem{{GUID: "latin-1", Title: "Crème"}},
+		},
+		{
+			name: "atom",
+			data: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Feed</title>
+  <link href="https://example.com/"/>
+  <entry>
+    <id>urn:uuid:1</id>
+    <title type="html">Entry &lt;b&gt;one&lt;/b&gt;</title>
+    <link rel="alternate" href="https://example.com/a"/>
+    <link rel="enclosure" href="https://example.com/a.mp3" type="audio/mpeg" length="42"/>
+    <published>2006-01-02T15:04:05Z</published>
+    <updated>2006-01-03T15:04:05Z</updated>
+    <summary>Short</summary>
+    <content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Long</div></content>
+    <author><name>Ann</name></author>
+    <author><name>Bob</name></author>
+  </entry>
+</feed>`,
+			title: "Atom Feed",
+			items: []RSSItem{{
+				GUID:        "urn:uuid:1",
+				Title:       "Entry <b>one</b>",
+				Link:        "https://example.com/a",
+				Description: "Short",
+				Content:     `<div xmlns="http://www.w3.org/1999/xhtml">Long</div>`,
+				PubDate:     "2006-01-02T15:04:05Z",
+				Updated:     "2006-01-03T15:04:05Z",
+				Creator:     "Ann, Bob",
+				Enclosures:  []RSSEnclosure{{URL: "https://example.com/a.mp3", Length: "42", Type: "audio/mpeg"}},
+			}},
+		},
+		{
+			name:        "json feed",
+			contentType: "application/feed+json",
+			data: `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "JSON Feed",
+  "items": [{
+    "id": "json-1",
+    "external_url": "https://example.com/j",
+    "title": "Item",
+    "content_text": "Text only",
+    "date_modified": "2006-01-02T15:04:05Z",
+    "authors": [{"name": "Cy"}],
+    "attachments": [{"url": "https://example.com/j.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 7, "duration_in_seconds": 61}]
+  }]
+}`,
+			title: "JSON Feed",
+			items: []RSSItem{{
+				GUID:           "json-1",
+				Title:          "Item",
+				Link:           "https://example.com/j",
+				Description:    "Text only",
+				Content:        "Text only",
+				PubDate:        "2006-01-02T15:04:05Z",
+				Updated:        "2006-01-02T15:04:05Z",
+				Creator:        "Cy",
+				ITunesDuration: "61",
+				Enclosures:     []RSSEnclosure{{URL: "https://example.com/j.mp3", Length: "7", Type: "audio/mpeg"}},
+			}},
+		},
+		{
+			name: "json feed with numeric ids",
+			data: `{
+  "version": "https://jsonfeed.org/version/1",
+  "title": "Numbers",
+  "items": [
+    {"id": 123, "url": "https://example.com/123", "date_published": "2006-01-02T15:04:05Z"},
+    {"id": 9007199254740993, "url": "https://example.com/big", "date_published": "2006-01-02T15:04:05Z"}
+  ]
+}`,
+			title: "Numbers",
+			items: []RSSItem{
+				{GUID: "123", Link: "https://example.com/123", PubDate: "2006-01-02T15:04:05Z"},
+				{GUID: "9007199254740993", Link: "https://example.com/big", PubDate: "2006-01-02T15:04:05Z"},
+			},
+		},
+		{
+			name: "rdf",
+			data: `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://example.com/">
+    <title>RDF Feed</title>
+    <link>https://example.com/</link>
+  </channel>
+  <item rdf:about="https://example.com/r">
+    <title>RDF item</title>
+    <description>About it</description>
+    <dc:date>2006-01-02T15:04Z</dc:date>
+    <dc:creator>Dee</dc:creator>
+  </item>
+</rdf:RDF>`,
+			title: "RDF Feed",
+			items: []RSSItem{{
+				GUID:        "https://example.com/r",
+				Title:       "RDF item",
+				Link:        "https://example.com/r",
+				Description: "About it",
+				PubDate:     "2006-01-02T15:04Z",
+				Creator:     "Dee",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := parseFeed([]byte(tt.data), tt.contentType)
+			if err != nil {
+				t.Fatalf("parseFeed: %v", err)
+			}
+			if feed.Channel.Title != tt.title {
+				t.Errorf("title = %q, want %q", feed.Channel.Title, tt.title)
+			}
+			if len(feed.Channel.Item) != len(tt.items) {
+				t.Fatalf("got %d items, want %d", len(feed.Channel.Item), len(tt.items))
+			}
+			for i, want := range tt.items {
+				got := feed.Channel.Item[i]
+				checks := []struct{ field, got, want string }{
+					{"GUID", got.GUID, want.GUID},
+					{"Title", got.Title, want.Title},
+					{"Link", got.Link, want.Link},
+					{"Description", got.Description, want.Description},
+					{"Content", got.Content, want.Content},
+					{"PubDate", got.PubDate, want.PubDate},
+					{"Updated", got.Updated, want.Updated},
+					{"Creator", got.Creator, want.Creator},
+					{"ITunesDuration", got.ITunesDuration, want.ITunesDuration},
+				}
+				for _, c := range checks {
+					if c.got != c.want {
+						t.Errorf("item %d %s = %q, want %q", i, c.field, c.got, c.want)
+					}
+				}
+				if len(got.Enclosures) != len(want.Enclosures) {
+					t.Fatalf("item %d has %d enclosures, want %d", i, len(got.Enclosures), len(want.Enclosures))
+				}
+				for j := range want.Enclosures {
+					if got.Enclosures[j] != want.Enclosures[j] {
+						t.Errorf("item %d enclosure %d = %+v, want %+v", i, j, got.Enclosures[j], want.Enclosures[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParsePublishedDate(t *testing.T) {
+	want := time.Date(2006, 1, 2, 15, 4, 0, 0, time.UTC)
+	tests := []string{
+		"Mon, 02 Jan 2006 15:04:00 +0000",
+		"Mon, 2 Jan 2006 15:04:00 +0000",
+		"Mon, 02 Jan 2006 15:04:00 UTC",
+		"02 Jan 06 15:04 +0000",
+		"2006-01-02T15:04:00Z",
+		"2006-01-02T16:04:00+01:00",
+		"2006-01-02T15:04Z",
+	}
+	for _, date := range tests {
+		got, err := parsePublishedDate(date)
+		if err != nil {
+			t.Errorf("parsePublishedDate(%q): %v", date, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parsePublishedDate(%q) = %s, want %s", date, got, want)
+		}
+	}
+
+	if _, err := parsePublishedDate("yesterday"); err == nil {
+		t.Error("parsePublishedDate(\"yesterday\") should fail")
+	}
+}
+
+func TestLegacyGUID(t *testing.T) {
+	tests := []struct {
+		name   string
+		item   RSSItem
+		legacy string
+		ok     bool
+	}{
+		{"atom id", RSSItem{GUID: "urn:uuid:1", Link: "https://example.com/a"}, "https://example.com/a", true},
+		{"permalink guid", RSSItem{GUID: "https://example.com/a", Link: "https://example.com/a"}, "", false},
+		{"no guid", RSSItem{Link: "https://example.com/a"}, "", false},
+		{"no link", RSSItem{GUID: "tag:example.com,2024:1"}, "", false},
+	}
+	for _, tt := range tests {
+		legacy, ok := legacyGUID(tt.item)
+		if ok != tt.ok || (ok && legacy != tt.legacy) {
+			t.Errorf("%s: legacyGUID = %q, %v, want %q, %v", tt.name, legacy, ok, tt.legacy, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// RDFFeed is an RSS 1.0 document. Unlike RSS 2.0 the items are siblings of
+// <channel> under the <rdf:RDF> root.
+type RDFFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Channel struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+
+		UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+	} `xml:"channel"`
+	Items []RDFItem `xml:"item"`
+}
+
+type RDFItem struct {
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+}
+
+func (f *RDFFeed) toRSSFeed() *RSSFeed {
+	var feed RSSFeed
+	feed.Channel.Title = strings.TrimSpace(f.Channel.Title)
+	feed.Channel.Link = strings.TrimSpace(f.Channel.Link)
+	feed.Channel.Description = strings.TrimSpace(f.Channel.Description)
+	feed.Channel.UpdatePeriod = strings.TrimSpace(f.Channel.UpdatePeriod)
+	feed.Channel.UpdateFrequency = strings.TrimSpace(f.Channel.UpdateFrequency)
+
+	for _, item := range f.Items {
+		link := strings.TrimSpace(item.Link)
+		if link == "" {
+			link = item.About
+		}
+
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			GUID:        item.About,
+			Title:       strings.TrimSpace(item.Title),
+			Link:        link,
+			Description: strings.TrimSpace(item.Description),
+			PubDate:     strings.TrimSpace(item.Date),
+			Content:     strings.TrimSpace(item.Content),
+			Creator:     strings.TrimSpace(item.Creator),
+		})
+	}
+
+	return &feed
+}
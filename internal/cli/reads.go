@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+func Read(s *state.State, cmd Command, user database.User) error {
+	usage := fmt.Errorf("usage: %s <post_id | --all | --feed name|url | --older-than duration>", cmd.Name)
+	args, flags, err := parseArgs(cmd.Args, "all")
+	if err != nil {
+		return usage
+	}
+
+	if len(args) == 1 && len(flags) == 0 {
+		postID, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid post id: %s", args[0])
+		}
+		if _, err := s.Db.GetPost(context.Background(), postID); err != nil {
+			return fmt.Errorf("couldn't get post: %w", err)
+		}
+
+		_, err = s.Db.MarkPostRead(context.Background(), database.MarkPostReadParams{
+			UserID: user.ID,
+			PostID: postID,
+			ReadAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't mark post as read: %w", err)
+		}
+		fmt.Printf("Marked %s as read\n", postID)
+		return nil
+	}
+
+	if len(args) != 0 || len(flags) == 0 {
+		return usage
+	}
+
+	params := database.MarkPostsReadParams{
+		UserID: user.ID,
+		ReadAt: time.Now().UTC(),
+	}
+	if feed, ok := flags["feed"]; ok {
+		params.Feed = sql.NullString{String: feed, Valid: true}
+	}
+	if _, ok := flags["older-than"]; ok {
+		age, err := durationFlag(flags, "older-than", 0)
+		if err != nil {
+			return err
+		}
+		params.PublishedBefore = sql.NullTime{Time: time.Now().UTC().Add(-age), Valid: true}
+	}
+	if !params.Feed.Valid && !params.PublishedBefore.Valid && flags["all"] != "true" {
+		return usage
+	}
+
+	marked, err := s.Db.MarkPostsRead(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("couldn't mark posts as read: %w", err)
+	}
+	fmt.Printf("Marked %d posts as read\n", marked)
+	return nil
+}
+
+func Unread(s *state.State, cmd Command, user database.User) error {
+	if len(cmd.Args) != 1 {
+		return fmt.Errorf("usage: %s <post_id>", cmd.Name)
+	}
+
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %s", cmd.Args[0])
+	}
+
+	unmarked, err := s.Db.MarkPostUnread(context.Background(), database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't mark post as unread: %w", err)
+	}
+	if unmarked == 0 {
+		fmt.Printf("%s was not read\n", postID)
+		return nil
+	}
+	fmt.Printf("Marked %s as unread\n", postID)
+	return nil
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+// moveFeed points feed to newURL after a permanent redirect and returns the
+// feed to keep scraping into. When newURL already belongs to another feed the
+// two are merged: follows, posts, filters and rules move to the existing feed
+// and the old one is deleted.
+func moveFeed(s *state.State, feed database.Feed, newURL string, statusCode int) (database.Feed, error) {
+	ctx := context.Background()
+
+	tx, err := s.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return feed, err
+	}
+	defer tx.Rollback()
+	q := s.Db.WithTx(tx)
+
+	target, err := q.GetFeedByUrl(ctx, sql.NullString{String: newURL, Valid: true})
+	merged := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return feed, err
+	}
+
+	if merged {
+		source := uuid.NullUUID{UUID: feed.ID, Valid: true}
+		dest := uuid.NullUUID{UUID: target.ID, Valid: true}
+
+		err = q.MoveFeedFollows(ctx, database.MoveFeedFollowsParams{TargetID: dest, SourceID: source})
+		if err != nil {
+			return feed, err
+		}
+		err = q.MoveFeedPosts(ctx, database.MoveFeedPostsParams{TargetID: dest, SourceID: source})
+		if err != nil {
+			return feed, err
+		}
+
+		// the posts left are the ones the target feed already has, keep the
+		// stars, read states and tags users gave them before they're deleted
+		err = q.MoveStarsToFeed(ctx, database.MoveStarsToFeedParams{SourceID: source, TargetID: dest})
+		if err != nil {
+			return feed, err
+		}
+		err = q.MoveReadsToFeed(ctx, database.MoveReadsToFeedParams{SourceID: source, TargetID: dest})
+		if err != nil {
+			return feed, err
+		}
+		err = q.MoveTagsToFeed(ctx, database.MoveTagsToFeedParams{SourceID: source, TargetID: dest})
+		if err != nil {
+			return feed, err
+		}
+		err = q.MovePostFiltersToFeed(ctx, database.MovePostFiltersToFeedParams{TargetID: dest, SourceID: source})
+		if err != nil {
+			return feed, err
+		}
+		err = q.MoveIngestRulesToFeed(ctx, database.MoveIngestRulesToFeedParams{TargetID: dest, SourceID: source})
+		if err != nil {
+			return feed, err
+		}
+		if err := q.DeleteFeed(ctx, feed.ID); err != nil {
+			return feed, err
+		}
+	} else {
+		err = q.UpdateFeedUrl(ctx, database.UpdateFeedUrlParams{
+			ID:  feed.ID,
+			Url: sql.NullString{String: newURL, Valid: true},
+		})
+		if err != nil {
+			return feed, err
+		}
+		target = feed
+		target.Url = sql.NullString{String: newURL, Valid: true}
+	}
+
+	_, err = q.CreateFeedUrlChange(ctx, database.CreateFeedUrlChangeParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		FeedID:     target.ID,
+		OldUrl:     feed.Url.String,
+		NewUrl:     newURL,
+		StatusCode: int32(statusCode),
+		Merged:     merged,
+	})
+	if err != nil {
+		return feed, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return feed, err
+	}
+
+	if merged {
+		log.Printf("feed %s permanently moved (%d) to %s, merged into feed %s", feed.Name, statusCode, newURL, target.Name)
+	} else {
+		log.Printf("feed %s permanently moved (%d) from %s to %s", feed.Name, statusCode, feed.Url.String, newURL)
+	}
+
+	return target, nil
+}
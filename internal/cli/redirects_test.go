@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchFeedRedirects(t *testing.T) {
+	redirects := map[string]struct {
+		to     string
+		status int
+	}{
+		"/permanent":      {"/permanent-next", http.StatusMovedPermanently},
+		"/permanent-next": {"/feed", http.StatusPermanentRedirect},
+		"/then-temporary": {"/temporary-next", http.StatusMovedPermanently},
+		"/temporary-next": {"/feed", http.StatusFound},
+		"/temporary":      {"/moved", http.StatusFound},
+		"/moved":          {"/feed", http.StatusMovedPermanently},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if redirect, ok := redirects[r.URL.Path]; ok {
+			http.Redirect(w, r, redirect.to, redirect.status)
+			return
+		}
+		w.Write([]byte(`<rss version="2.0"><channel><title>Feed</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path       string
+		movedTo    string
+		statusCode int
+	}{
+		{"/feed", "", 0},
+		{"/permanent", "/feed", http.StatusPermanentRedirect},
+		{"/then-temporary", "/temporary-next", http.StatusMovedPermanently},
+		{"/temporary", "", 0},
+	}
+	for _, tt := range tests {
+		result, err := fetchFeed(context.Background(), server.URL+tt.path, feedCache{})
+		if err != nil {
+			t.Errorf("fetchFeed(%s): %v", tt.path, err)
+			continue
+		}
+
+		movedTo := ""
+		if tt.movedTo != "" {
+			movedTo = server.URL + tt.movedTo
+		}
+		if result.MovedTo != movedTo || result.MovedStatusCode != tt.statusCode {
+			t.Errorf("fetchFeed(%s) moved to %q with %d, want %q with %d", tt.path, result.MovedTo, result.MovedStatusCode, movedTo, tt.statusCode)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+type retentionPolicy struct {
+	MaxAge   time.Duration
+	KeepLast int
+}
+
+func (p retentionPolicy) String() string {
+	switch {
+	case p.MaxAge > 0 && p.KeepLast > 0:
+		return fmt.Sprintf("keep %s, at most the last %d posts", formatAge(p.MaxAge), p.KeepLast)
+	case p.MaxAge > 0:
+		return fmt.Sprintf("keep %s", formatAge(p.MaxAge))
+	case p.KeepLast > 0:
+		return fmt.Sprintf("keep the last %d posts", p.KeepLast)
+	default:
+		return "keep forever"
+	}
+}
+
+func formatAge(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// defaultRetention is the policy of the feeds without their own, read from the
+// retention_max_age and retention_keep_last settings of the config.
+func defaultRetention(s *state.State) (retentionPolicy, error) {
+	policy := retentionPolicy{KeepLast: s.Cfg.RetentionKeepLast}
+	if s.Cfg.RetentionMaxAge != "" {
+		maxAge, err := parseDuration(s.Cfg.RetentionMaxAge)
+		if err != nil {
+			return retentionPolicy{}, fmt.Errorf("invalid retention_max_age in config: %s", s.Cfg.RetentionMaxAge)
+		}
+		policy.MaxAge = maxAge
+	}
+	return policy, nil
+}
+
+// feedRetention returns the override of feed if it has one, the default
+// otherwise. A zero field of an override means no limit.
+func feedRetention(feed database.Feed, fallback retentionPolicy) retentionPolicy {
+	if !feed.RetentionMaxAgeSeconds.Valid && !feed.RetentionKeepLast.Valid {
+		return fallback
+	}
+	return retentionPolicy{
+		MaxAge:   time.Duration(feed.RetentionMaxAgeSeconds.Int32) * time.Second,
+		KeepLast: int(feed.RetentionKeepLast.Int32),
+	}
+}
+
+// retentionCutoff returns the publication time before which items of feed
+// are left out by scrapeFeed, since prune would delete them and the next
+// fetch would add them back as new posts: the max age, and with keep last the
+// Nth newest stored post.
+func retentionCutoff(s *state.State, feed database.Feed) (time.Time, error) {
+	fallback, err := defaultRetention(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	policy := feedRetention(feed, fallback)
+
+	var cutoff time.Time
+	if policy.MaxAge > 0 {
+		cutoff = time.Now().UTC().Add(-policy.MaxAge)
+	}
+	if policy.KeepLast > 0 {
+		nth, err := s.Db.GetKeepLastCutoff(context.Background(), database.GetKeepLastCutoffParams{
+			FeedID:   uuid.NullUUID{UUID: feed.ID, Valid: true},
+			KeepLast: int32(policy.KeepLast),
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, err
+		}
+		// no row when the feed has fewer posts than it keeps
+		if err == nil && nth.After(cutoff) {
+			cutoff = nth
+		}
+	}
+	return cutoff, nil
+}
+
+// prunePosts deletes the posts past the retention policy of their feed, except
+// for the starred ones. With dryRun nothing is deleted and the counts are of
+// the posts that would be.
+func prunePosts(s *state.State, dryRun bool) ([]database.CountExpiredPostsRow, error) {
+	policy, err := defaultRetention(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return s.Db.CountExpiredPosts(context.Background(), database.CountExpiredPostsParams{
+			DefaultMaxAgeSeconds: int32(policy.MaxAge.Seconds()),
+			DefaultKeepLast:      int32(policy.KeepLast),
+		})
+	}
+
+	pruned, err := s.Db.PruneExpiredPosts(context.Background(), database.PruneExpiredPostsParams{
+		DefaultMaxAgeSeconds: int32(policy.MaxAge.Seconds()),
+		DefaultKeepLast:      int32(policy.KeepLast),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]database.CountExpiredPostsRow, len(pruned))
+	for i, row := range pruned {
+		counts[i] = database.CountExpiredPostsRow(row)
+	}
+	return counts, nil
+}
+
+// pruneFetchLog deletes the fetch log entries health no longer looks at, but
+// keeps the latest one of each feed for its last status and error.
+func pruneFetchLog(s *state.State) (int64, error) {
+	return s.Db.DeleteFeedFetchLogsBefore(context.Background(), time.Now().UTC().Add(-healthWindow))
+}
+
+func Prune(s *state.State, cmd Command) error {
+	args, flags, err := parseArgs(cmd.Args, "dry-run")
+	if err != nil || len(args) != 0 {
+		return fmt.Errorf("usage: %s [--dry-run]", cmd.Name)
+	}
+	dryRun := flags["dry-run"] == "true"
+
+	counts, err := prunePosts(s, dryRun)
+	if err != nil {
+		return fmt.Errorf("couldn't prune posts: %w", err)
+	}
+
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	total := 0
+	for _, count := range counts {
+		fmt.Printf("* %-30s %d\n", count.FeedName, count.Posts)
+		total += int(count.Posts)
+	}
+	fmt.Printf("%s %d posts (starred posts are always kept).\n", verb, total)
+
+	if !dryRun {
+		deleted, err := pruneFetchLog(s)
+		if err != nil {
+			return fmt.Errorf("couldn't prune the fetch log: %w", err)
+		}
+		fmt.Printf("Deleted %d fetch log entries older than %s.\n", deleted, formatAge(healthWindow))
+	}
+	return nil
+}
+
+// pruneLoop runs prunePosts and pruneFetchLog every interval for agg --prune.
+func pruneLoop(s *state.State, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for ; ; <-ticker.C {
+		counts, err := prunePosts(s, false)
+		if err != nil {
+			log.Printf("failed to prune posts: %v", err)
+		}
+		for _, count := range counts {
+			log.Printf("pruned %d posts of %s", count.Posts, count.FeedName)
+		}
+
+		if _, err := pruneFetchLog(s); err != nil {
+			log.Printf("failed to prune the fetch log: %v", err)
+		}
+	}
+}
+
+func Retention(s *state.State, cmd Command) error {
+	usage := fmt.Errorf("usage: %s [<feed_url> [--max-age duration] [--keep-last N] [--forever] [--default]]", cmd.Name)
+	args, flags, err := parseArgs(cmd.Args, "forever", "default")
+	if err != nil || len(args) > 1 {
+		return usage
+	}
+
+	fallback, err := defaultRetention(s)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Default: %s\n", fallback)
+		feeds, err := s.Db.GetFeeds(context.Background())
+		if err != nil {
+			return fmt.Errorf("couldn't get feeds: %w", err)
+		}
+		for _, feed := range feeds {
+			if feed.RetentionMaxAgeSeconds.Valid || feed.RetentionKeepLast.Valid {
+				fmt.Printf("* %s: %s\n", feed.Name, retentionPolicy{
+					MaxAge:   time.Duration(feed.RetentionMaxAgeSeconds.Int32) * time.Second,
+					KeepLast: int(feed.RetentionKeepLast.Int32),
+				})
+			}
+		}
+		return nil
+	}
+
+	feed, err := s.Db.GetFeedByUrl(context.Background(), sql.NullString{String: args[0], Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no feed found with url %s", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get feed: %w", err)
+	}
+
+	params := database.SetFeedRetentionParams{ID: feed.ID}
+	switch {
+	case flags["default"] == "true":
+		// both NULL, back to the default policy
+	case flags["forever"] == "true":
+		params.RetentionMaxAgeSeconds = sql.NullInt32{Int32: 0, Valid: true}
+		params.RetentionKeepLast = sql.NullInt32{Int32: 0, Valid: true}
+	default:
+		maxAge, err := durationFlag(flags, "max-age", 0)
+		if err != nil {
+			return err
+		}
+		keepLast, err := intFlag(flags, "keep-last", 0)
+		if err != nil {
+			return err
+		}
+		if maxAge == 0 && keepLast == 0 {
+			return usage
+		}
+		params.RetentionMaxAgeSeconds = sql.NullInt32{Int32: int32(maxAge.Seconds()), Valid: true}
+		params.RetentionKeepLast = sql.NullInt32{Int32: int32(keepLast), Valid: true}
+	}
+
+	if err := s.Db.SetFeedRetention(context.Background(), params); err != nil {
+		return fmt.Errorf("couldn't set retention: %w", err)
+	}
+
+	feed.RetentionMaxAgeSeconds = params.RetentionMaxAgeSeconds
+	feed.RetentionKeepLast = params.RetentionKeepLast
+	fmt.Printf("%s: %s\n", feed.Name, feedRetention(feed, fallback))
+	return nil
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+func contentHash(title, link, description, content string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{title, link, description, content}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func itemHash(item RSSItem) string {
+	return contentHash(item.Title, item.Link, item.Description, item.Content)
+}
+
+func postHash(post database.Post) string {
+	if post.ContentHash.Valid {
+		return post.ContentHash.String
+	}
+	return contentHash(post.Title.String, post.Url.String, post.Description.String, post.Content.String)
+}
+
+func parseOptionalDate(date string) sql.NullTime {
+	if date == "" {
+		return sql.NullTime{}
+	}
+	t, err := parsePublishedDate(date)
+	if err != nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// updateChangedPost compares a re-fetched item with the stored post. When the
+// item changed the previous version is kept in post_revisions and the post is
+// updated in place. The returned bool reports whether anything was written.
+func updateChangedPost(s *state.State, feedID uuid.UUID, item RSSItem) (database.Post, bool, error) {
+	existing, err := s.Db.GetPostByFeedAndGuid(context.Background(), database.GetPostByFeedAndGuidParams{
+		FeedID: uuid.NullUUID{UUID: feedID, Valid: true},
+		Guid:   itemGUID(item),
+	})
+	if err != nil {
+		return database.Post{}, false, err
+	}
+
+	// an updated timestamp that didn't move means the item didn't change
+	updatedAt := parseOptionalDate(item.Updated)
+	if updatedAt.Valid && existing.SourceUpdatedAt.Valid && !updatedAt.Time.After(existing.SourceUpdatedAt.Time) {
+		return existing, false, nil
+	}
+
+	hash := itemHash(item)
+	if hash == postHash(existing) {
+		return existing, false, nil
+	}
+
+	_, err = s.Db.CreatePostRevision(context.Background(), database.CreatePostRevisionParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now().UTC(),
+		PostID:      existing.ID,
+		Title:       existing.Title,
+		Url:         existing.Url,
+		Description: existing.Description,
+		Content:     existing.Content,
+	})
+	if err != nil {
+		return database.Post{}, false, fmt.Errorf("couldn't save revision: %w", err)
+	}
+
+	post, err := s.Db.UpdatePost(context.Background(), database.UpdatePostParams{
+		ID:              existing.ID,
+		Title:           sql.NullString{String: item.Title, Valid: true},
+		Url:             sql.NullString{String: item.Link, Valid: item.Link != ""},
+		Description:     sql.NullString{String: item.Description, Valid: true},
+		Content:         sql.NullString{String: item.Content, Valid: item.Content != ""},
+		ContentHash:     sql.NullString{String: hash, Valid: true},
+		SourceUpdatedAt: updatedAt,
+		UpdatedAt:       time.Now().UTC(),
+	})
+	if err != nil {
+		return database.Post{}, false, err
+	}
+
+	return post, true, nil
+}
+
+func Post(s *state.State, cmd Command) error {
+	if len(cmd.Args) != 2 || cmd.Args[0] != "diff" {
+		return fmt.Errorf("usage: %s diff <post_id>", cmd.Name)
+	}
+
+	postID, err := uuid.Parse(cmd.Args[1])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %s", cmd.Args[1])
+	}
+
+	post, err := s.Db.GetPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("couldn't get post: %w", err)
+	}
+
+	revisions, err := s.Db.GetRevisionsForPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("couldn't get post revisions: %w", err)
+	}
+
+	if len(revisions) == 0 {
+		fmt.Println("This post has never been edited.")
+		return nil
+	}
+
+	// each revision holds the version that was replaced at its created_at,
+	// so it is compared with the next revision or with the current post
+	for i, revision := range revisions {
+		next := database.PostRevision{
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: post.Description,
+			Content:     post.Content,
+		}
+		if i+1 < len(revisions) {
+			next = revisions[i+1]
+		}
+
+		fmt.Printf("Edited on %s\n", revision.CreatedAt.Format(time.RFC1123))
+		printFieldDiff("Title", revision.Title.String, next.Title.String)
+		printFieldDiff("URL", revision.Url.String, next.Url.String)
+		printFieldDiff("Description", htmlToText(revision.Description.String), htmlToText(next.Description.String))
+		printFieldDiff("Content", htmlToText(revision.Content.String), htmlToText(next.Content.String))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func printFieldDiff(field, before, after string) {
+	if before == after {
+		return
+	}
+
+	fmt.Printf("* %s:\n", field)
+	for _, line := range diffLines(strings.Split(before, "\n"), strings.Split(after, "\n")) {
+		fmt.Println(line)
+	}
+}
+
+// diffLines returns a line by line diff of a and b built from their longest
+// common subsequence, prefixing removed lines with "-" and added ones with "+".
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+
+	return lines
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{"equal", []string{"a", "b"}, []string{"a", "b"}, []string{"  a", "  b"}},
+		{"added", []string{"a"}, []string{"a", "b"}, []string{"  a", "+ b"}},
+		{"removed", []string{"a", "b"}, []string{"b"}, []string{"- a", "  b"}},
+		{"changed", []string{"a", "b", "c"}, []string{"a", "x", "c"}, []string{"  a", "- b", "+ x", "  c"}},
+		{"from nothing", nil, []string{"a"}, []string{"+ a"}},
+	}
+	for _, tt := range tests {
+		if got := diffLines(tt.a, tt.b); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: diffLines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+var ruleActions = []string{"mark-read", "star", "tag", "notify"}
+
+// notifyTimeout bounds notify_command, which runs inside a scrape worker.
+const notifyTimeout = 30 * time.Second
+
+type ruleCheck struct {
+	Condition string
+	Matched   bool
+}
+
+// checkRule evaluates every condition of rule against post, categoryID being
+// the category the owner of the rule files the post's feed under. The rule
+// applies when all of them match, a rule without conditions matches any post.
+func checkRule(rule database.IngestRule, post database.Post, categoryID uuid.NullUUID, now time.Time) []ruleCheck {
+	var checks []ruleCheck
+	if rule.FeedID.Valid {
+		checks = append(checks, ruleCheck{"feed", post.FeedID == rule.FeedID})
+	}
+	if rule.CategoryID.Valid {
+		checks = append(checks, ruleCheck{"category", categoryID == rule.CategoryID})
+	}
+	if rule.TitlePattern.Valid {
+		re, err := regexp.Compile("(?i)" + rule.TitlePattern.String)
+		checks = append(checks, ruleCheck{fmt.Sprintf("title matches %q", rule.TitlePattern.String), err == nil && re.MatchString(post.Title.String)})
+	}
+
+	age := now.Sub(post.PublishedAt)
+	if rule.MinAgeSeconds.Valid {
+		minAge := time.Duration(rule.MinAgeSeconds.Int32) * time.Second
+		checks = append(checks, ruleCheck{fmt.Sprintf("older than %s", minAge), age >= minAge})
+	}
+	if rule.MaxAgeSeconds.Valid {
+		maxAge := time.Duration(rule.MaxAgeSeconds.Int32) * time.Second
+		checks = append(checks, ruleCheck{fmt.Sprintf("newer than %s", maxAge), age < maxAge})
+	}
+
+	return checks
+}
+
+func ruleMatches(checks []ruleCheck) bool {
+	for _, check := range checks {
+		if !check.Matched {
+			return false
+		}
+	}
+	return true
+}
+
+// runIngestRules applies to a newly created post the rules of every user
+// following its feed. Each user's rules run by position, until one marked
+// stop matches.
+func runIngestRules(s *state.State, feed database.Feed, post database.Post) {
+	rules, err := s.Db.GetIngestRulesForFeed(context.Background(), uuid.NullUUID{UUID: feed.ID, Valid: true})
+	if err != nil {
+		log.Printf("failed to get rules for feed %s: %v", feed.Name, err)
+		return
+	}
+
+	for _, rule := range matchingRules(rules, post, time.Now().UTC()) {
+		if err := applyRule(s, rule, feed, post); err != nil {
+			log.Printf("failed to apply rule %s to post '%s': %v", rule.Name, post.Title.String, err)
+		}
+	}
+}
+
+// matchingRules returns the rules to apply to post, in order. rows come
+// sorted by user and position, and a matching rule marked stop skips the
+// following rules of its user only.
+func matchingRules(rows []database.GetIngestRulesForFeedRow, post database.Post, now time.Time) []database.IngestRule {
+	var matching []database.IngestRule
+	stopped := map[uuid.UUID]bool{}
+	for _, row := range rows {
+		rule := ingestRule(row)
+		if stopped[rule.UserID] || !ruleMatches(checkRule(rule, post, row.FollowCategoryID, now)) {
+			continue
+		}
+		matching = append(matching, rule)
+		stopped[rule.UserID] = rule.Stop
+	}
+	return matching
+}
+
+func ingestRule(row database.GetIngestRulesForFeedRow) database.IngestRule {
+	return database.IngestRule{
+		ID:            row.ID,
+		CreatedAt:     row.CreatedAt,
+		UserID:        row.UserID,
+		Name:          row.Name,
+		Position:      row.Position,
+		FeedID:        row.FeedID,
+		CategoryID:    row.CategoryID,
+		TitlePattern:  row.TitlePattern,
+		MinAgeSeconds: row.MinAgeSeconds,
+		MaxAgeSeconds: row.MaxAgeSeconds,
+		Action:        row.Action,
+		Tag:           row.Tag,
+		Stop:          row.Stop,
+	}
+}
+
+func applyRule(s *state.State, rule database.IngestRule, feed database.Feed, post database.Post) error {
+	switch rule.Action {
+	case "mark-read":
+		_, err := s.Db.MarkPostRead(context.Background(), database.MarkPostReadParams{
+			UserID: rule.UserID,
+			PostID: post.ID,
+			ReadAt: time.Now().UTC(),
+		})
+		return err
+	case "star":
+		_, err := s.Db.StarPost(context.Background(), database.StarPostParams{
+			UserID:    rule.UserID,
+			PostID:    post.ID,
+			CreatedAt: time.Now().UTC(),
+			Note:      sql.NullString{String: "starred by rule " + rule.Name, Valid: true},
+		})
+		return err
+	case "tag":
+		return s.Db.TagPost(context.Background(), database.TagPostParams{
+			UserID:    rule.UserID,
+			PostID:    post.ID,
+			Tag:       rule.Tag.String,
+			CreatedAt: time.Now().UTC(),
+		})
+	case "notify":
+		return notify(s, feed, post)
+	default:
+		return fmt.Errorf("unknown action %s", rule.Action)
+	}
+}
+
+// notify logs the post and, when notify_command is set in the config, runs it
+// with the post in the GATOR_FEED, GATOR_TITLE and GATOR_URL variables.
+func notify(s *state.State, feed database.Feed, post database.Post) error {
+	log.Printf("new post in %s: %s %s", feed.Name, post.Title.String, post.Url.String)
+	if s.Cfg.NotifyCommand == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Cfg.NotifyCommand)
+	// don't wait for children of the shell still holding the output open
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(),
+		"GATOR_FEED="+feed.Name,
+		"GATOR_TITLE="+post.Title.String,
+		"GATOR_URL="+post.Url.String,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify command failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func Rule(s *state.State, cmd Command, user database.User) error {
+	usage := fmt.Errorf("usage: %s add <name> <mark-read|star|tag|notify> [tag] [--feed name|url] [--category name] [--title regex] [--older-than duration] [--newer-than duration] [--position N] [--stop] | list | rm <name> | test <name> --post <post_id>", cmd.Name)
+	if len(cmd.Args) == 0 {
+		return usage
+	}
+
+	switch cmd.Args[0] {
+	case "add":
+		args, flags, err := parseArgs(cmd.Args[1:], "stop")
+		if err != nil || len(args) < 2 || len(args) > 3 {
+			return usage
+		}
+		return addRule(s, user, args, flags)
+
+	case "list":
+		if len(cmd.Args) != 1 {
+			return usage
+		}
+		return listRules(s, user)
+
+	case "rm":
+		if len(cmd.Args) != 2 {
+			return usage
+		}
+		deleted, err := s.Db.DeleteIngestRule(context.Background(), database.DeleteIngestRuleParams{
+			UserID: user.ID,
+			Name:   cmd.Args[1],
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't delete rule: %w", err)
+		}
+		if deleted == 0 {
+			return fmt.Errorf("no rule named %s", cmd.Args[1])
+		}
+		fmt.Printf("Deleted rule %s\n", cmd.Args[1])
+		return nil
+
+	case "test":
+		args, flags, err := parseArgs(cmd.Args[1:])
+		if err != nil || len(args) != 1 || flags["post"] == "" {
+			return usage
+		}
+		return testRule(s, user, args[0], flags["post"])
+
+	default:
+		return usage
+	}
+}
+
+func addRule(s *state.State, user database.User, args []string, flags map[string]string) error {
+	name, action := args[0], args[1]
+	if !slices.Contains(ruleActions, action) {
+		return fmt.Errorf("unknown action %s, expected one of %s", action, strings.Join(ruleActions, ", "))
+	}
+
+	var tag sql.NullString
+	if action == "tag" {
+		if len(args) != 3 {
+			return fmt.Errorf("the tag action needs a tag name")
+		}
+		tag = sql.NullString{String: args[2], Valid: true}
+	} else if len(args) == 3 {
+		return fmt.Errorf("only the tag action takes an argument")
+	}
+
+	params := database.CreateIngestRuleParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		Name:      name,
+		Action:    action,
+		Tag:       tag,
+		Stop:      flags["stop"] == "true",
+	}
+
+	if feed, ok := flags["feed"]; ok {
+		follow, err := findFollowedFeed(s, user, feed)
+		if err != nil {
+			return err
+		}
+		params.FeedID = follow.FeedID
+	}
+	if category, ok := flags["category"]; ok {
+		c, err := s.Db.GetCategoryByName(context.Background(), database.GetCategoryByNameParams{
+			UserID: user.ID,
+			Name:   category,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no category named %s, create it with category add", category)
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't get category: %w", err)
+		}
+		params.CategoryID = uuid.NullUUID{UUID: c.ID, Valid: true}
+	}
+	if title, ok := flags["title"]; ok {
+		if _, err := regexp.Compile(title); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		params.TitlePattern = sql.NullString{String: title, Valid: true}
+	}
+	if _, ok := flags["older-than"]; ok {
+		age, err := durationFlag(flags, "older-than", 0)
+		if err != nil {
+			return err
+		}
+		params.MinAgeSeconds = sql.NullInt32{Int32: int32(age.Seconds()), Valid: true}
+	}
+	if _, ok := flags["newer-than"]; ok {
+		age, err := durationFlag(flags, "newer-than", 0)
+		if err != nil {
+			return err
+		}
+		params.MaxAgeSeconds = sql.NullInt32{Int32: int32(age.Seconds()), Valid: true}
+	}
+
+	// new rules run last unless given a position
+	rules, err := s.Db.GetIngestRulesForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get rules: %w", err)
+	}
+	position := 1
+	if len(rules) > 0 {
+		position = int(rules[len(rules)-1].Position) + 1
+	}
+	if position, err = intFlag(flags, "position", position); err != nil {
+		return err
+	}
+	params.Position = int32(position)
+
+	rule, err := s.Db.CreateIngestRule(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("couldn't create rule: %w", err)
+	}
+
+	fmt.Printf("Created rule %s at position %d\n", rule.Name, rule.Position)
+	return nil
+}
+
+func listRules(s *state.State, user database.User) error {
+	rules, err := s.Db.GetIngestRulesForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get rules: %w", err)
+	}
+
+	if len(rules) == 0 {
+		fmt.Println("No rules.")
+		return nil
+	}
+	for _, rule := range rules {
+		action := rule.Action
+		if rule.Tag.Valid {
+			action += " " + rule.Tag.String
+		}
+
+		var conditions []string
+		if rule.FeedName.Valid {
+			conditions = append(conditions, "feed "+rule.FeedName.String)
+		}
+		if rule.CategoryName.Valid {
+			conditions = append(conditions, "category "+rule.CategoryName.String)
+		}
+		if rule.TitlePattern.Valid {
+			conditions = append(conditions, fmt.Sprintf("title matches %q", rule.TitlePattern.String))
+		}
+		if rule.MinAgeSeconds.Valid {
+			conditions = append(conditions, fmt.Sprintf("older than %s", time.Duration(rule.MinAgeSeconds.Int32)*time.Second))
+		}
+		if rule.MaxAgeSeconds.Valid {
+			conditions = append(conditions, fmt.Sprintf("newer than %s", time.Duration(rule.MaxAgeSeconds.Int32)*time.Second))
+		}
+		if len(conditions) == 0 {
+			conditions = append(conditions, "any post")
+		}
+
+		fmt.Printf("%d. %s: %s when %s", rule.Position, rule.Name, action, strings.Join(conditions, " and "))
+		if rule.Stop {
+			fmt.Print(", then stop")
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// testRule is a dry run of a rule against a stored post.
+func testRule(s *state.State, user database.User, name, postArg string) error {
+	rule, err := s.Db.GetIngestRuleByName(context.Background(), database.GetIngestRuleByNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't get rule %s: %w", name, err)
+	}
+
+	postID, err := uuid.Parse(postArg)
+	if err != nil {
+		return fmt.Errorf("invalid post id: %s", postArg)
+	}
+	post, err := s.Db.GetPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("couldn't get post: %w", err)
+	}
+
+	var categoryID uuid.NullUUID
+	feedFollows, err := s.Db.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("couldn't get feed follows: %w", err)
+	}
+	for _, ff := range feedFollows {
+		if ff.FeedID == post.FeedID {
+			categoryID = ff.CategoryID
+		}
+	}
+
+	checks := checkRule(rule, post, categoryID, time.Now().UTC())
+	fmt.Printf("Rule %s on %s:\n", rule.Name, post.Title.String)
+	for _, check := range checks {
+		result := "no"
+		if check.Matched {
+			result = "yes"
+		}
+		fmt.Printf("* %-30s %s\n", check.Condition, result)
+	}
+
+	if !ruleMatches(checks) {
+		fmt.Println("The rule doesn't apply, nothing would happen.")
+		return nil
+	}
+	action := rule.Action
+	if rule.Tag.Valid {
+		action += " " + rule.Tag.String
+	}
+	fmt.Printf("The rule applies: %s (not performed, this is a dry run).\n", action)
+	return nil
+}
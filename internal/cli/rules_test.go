@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestCheckRule(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	feedID := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	otherID := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	post := database.Post{
+		FeedID:      feedID,
+		Title:       sql.NullString{String: "Go 1.22 is released", Valid: true},
+		PublishedAt: now.Add(-time.Hour),
+	}
+	seconds := func(d time.Duration) sql.NullInt32 {
+		return sql.NullInt32{Int32: int32(d.Seconds()), Valid: true}
+	}
+	pattern := func(p string) sql.NullString {
+		return sql.NullString{String: p, Valid: true}
+	}
+
+	tests := []struct {
+		name       string
+		rule       database.IngestRule
+		categoryID uuid.NullUUID
+		want       bool
+	}{
+		{"no conditions", database.IngestRule{}, uuid.NullUUID{}, true},
+		{"feed", database.IngestRule{FeedID: feedID}, uuid.NullUUID{}, true},
+		{"other feed", database.IngestRule{FeedID: otherID}, uuid.NullUUID{}, false},
+		{"category", database.IngestRule{CategoryID: otherID}, otherID, true},
+		{"uncategorized feed", database.IngestRule{CategoryID: otherID}, uuid.NullUUID{}, false},
+		{"title ignores case", database.IngestRule{TitlePattern: pattern(`^go \d`)}, uuid.NullUUID{}, true},
+		{"title", database.IngestRule{TitlePattern: pattern("rust")}, uuid.NullUUID{}, false},
+		{"invalid title pattern", database.IngestRule{TitlePattern: pattern("(")}, uuid.NullUUID{}, false},
+		{"older than, inclusive", database.IngestRule{MinAgeSeconds: seconds(time.Hour)}, uuid.NullUUID{}, true},
+		{"older than", database.IngestRule{MinAgeSeconds: seconds(2 * time.Hour)}, uuid.NullUUID{}, false},
+		{"newer than", database.IngestRule{MaxAgeSeconds: seconds(2 * time.Hour)}, uuid.NullUUID{}, true},
+		{"newer than, exclusive", database.IngestRule{MaxAgeSeconds: seconds(time.Hour)}, uuid.NullUUID{}, false},
+		{"all conditions match", database.IngestRule{FeedID: feedID, TitlePattern: pattern("released"), MaxAgeSeconds: seconds(2 * time.Hour)}, uuid.NullUUID{}, true},
+		{"one condition fails", database.IngestRule{FeedID: feedID, TitlePattern: pattern("released"), MinAgeSeconds: seconds(2 * time.Hour)}, uuid.NullUUID{}, false},
+	}
+	for _, tt := range tests {
+		if got := ruleMatches(checkRule(tt.rule, post, tt.categoryID, now)); got != tt.want {
+			t.Errorf("%s: ruleMatches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchingRules(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	post := database.Post{
+		Title:       sql.NullString{String: "Weekly news", Valid: true},
+		PublishedAt: now,
+	}
+	alice, bob := uuid.New(), uuid.New()
+	rule := func(user uuid.UUID, name, title string, stop bool) database.GetIngestRulesForFeedRow {
+		return database.GetIngestRulesForFeedRow{
+			UserID:       user,
+			Name:         name,
+			TitlePattern: sql.NullString{String: title, Valid: title != ""},
+			Stop:         stop,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		rules []database.GetIngestRulesForFeedRow
+		want  []string
+	}{
+		{
+			name:  "in order",
+			rules: []database.GetIngestRulesForFeedRow{rule(alice, "first", "", false), rule(alice, "second", "news", false)},
+			want:  []string{"first", "second"},
+		},
+		{
+			name:  "stop skips the following rules",
+			rules: []database.GetIngestRulesForFeedRow{rule(alice, "first", "", true), rule(alice, "second", "", false)},
+			want:  []string{"first"},
+		},
+		{
+			name:  "stop only applies when the rule matches",
+			rules: []database.GetIngestRulesForFeedRow{rule(alice, "first", "sports", true), rule(alice, "second", "", false)},
+			want:  []string{"second"},
+		},
+		{
+			name: "stop is per user",
+			rules: []database.GetIngestRulesForFeedRow{
+				rule(alice, "alice first", "", true),
+				rule(alice, "alice second", "", false),
+				rule(bob, "bob first", "", false),
+				rule(bob, "bob second", "", true),
+				rule(bob, "bob third", "", false),
+			},
+			want: []string{"alice first", "bob first", "bob second"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, rule := range matchingRules(tt.rules, post, now) {
+			got = append(got, rule.Name)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: matchingRules = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minFetchInterval     = 10 * time.Minute
+	maxFetchInterval     = 24 * time.Hour
+	defaultFetchInterval = time.Hour
+)
+
+// SkipHours and SkipDays are the RSS hints telling aggregators when not to
+// poll the feed. Hours are in GMT. Like <ttl> they are kept as strings so that
+// a malformed value doesn't make the whole feed fail to parse.
+type SkipHours struct {
+	Hours []string `xml:"hour"`
+}
+
+type SkipDays struct {
+	Days []string `xml:"day"`
+}
+
+// syndicationPeriods maps sy:updatePeriod values to their duration.
+var syndicationPeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// fetchInterval picks how often a feed should be polled. It starts from half
+// the average gap between its most recent posts, so a news wire is polled far
+// more often than a daily blog, and never goes below the <ttl> or the
+// sy:updatePeriod/sy:updateFrequency the publisher asks for.
+func fetchInterval(feed *RSSFeed) time.Duration {
+	interval := observedInterval(feed.Channel.Item)
+
+	if ttl, err := strconv.Atoi(strings.TrimSpace(feed.Channel.TTL)); err == nil && ttl > 0 {
+		interval = max(interval, time.Duration(ttl)*time.Minute)
+	}
+
+	if period, ok := syndicationPeriods[strings.TrimSpace(feed.Channel.UpdatePeriod)]; ok {
+		frequency, err := strconv.Atoi(strings.TrimSpace(feed.Channel.UpdateFrequency))
+		if err != nil || frequency < 1 {
+			frequency = 1
+		}
+		interval = max(interval, period/time.Duration(frequency))
+	}
+
+	return min(max(interval, minFetchInterval), maxFetchInterval)
+}
+
+func observedInterval(items []RSSItem) time.Duration {
+	var dates []time.Time
+	for _, item := range items {
+		if t, err := parsePublishedDate(item.PubDate); err == nil {
+			dates = append(dates, t)
+		}
+	}
+	if len(dates) < 2 {
+		return defaultFetchInterval
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return b.Compare(a) })
+	dates = dates[:min(len(dates), 10)]
+
+	gap := dates[0].Sub(dates[len(dates)-1]) / time.Duration(len(dates)-1)
+	return gap / 2
+}
+
+// nextFetchTime returns the first time after now+interval that isn't in the
+// skipHours or skipDays of the feed.
+func nextFetchTime(feed *RSSFeed, now time.Time, interval time.Duration) time.Time {
+	next := now.UTC().Add(interval)
+
+	// a week of hours is enough to get out of any combination of skips
+	for range 7 * 24 {
+		skipHour := slices.ContainsFunc(feed.Channel.SkipHours.Hours, func(hour string) bool {
+			h, err := strconv.Atoi(strings.TrimSpace(hour))
+			// 24 is sometimes used for midnight
+			return err == nil && h%24 == next.Hour()
+		})
+		skipDay := slices.ContainsFunc(feed.Channel.SkipDays.Days, func(day string) bool {
+			return strings.EqualFold(strings.TrimSpace(day), next.Weekday().String())
+		})
+		if !skipHour && !skipDay {
+			return next
+		}
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+
+	return now.UTC().Add(interval)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFetchInterval(t *testing.T) {
+	items := func(gap time.Duration) []RSSItem {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		var items []RSSItem
+		for i := range 3 {
+			items = append(items, RSSItem{PubDate: start.Add(time.Duration(i) * gap).Format(time.RFC1123Z)})
+		}
+		return items
+	}
+
+	tests := []struct {
+		name  string
+		setup func(feed *RSSFeed)
+		want  time.Duration
+	}{
+		{"no dates", func(feed *RSSFeed) {}, defaultFetchInterval},
+		{"half the gap", func(feed *RSSFeed) { feed.Channel.Item = items(4 * time.Hour) }, 2 * time.Hour},
+		{"at least minFetchInterval", func(feed *RSSFeed) { feed.Channel.Item = items(time.Minute) }, minFetchInterval},
+		{"at most maxFetchInterval", func(feed *RSSFeed) { feed.Channel.Item = items(7 * 24 * time.Hour) }, maxFetchInterval},
+		{"ttl", func(feed *RSSFeed) {
+			feed.Channel.Item = items(time.Hour)
+			feed.Channel.TTL = "90"
+		}, 90 * time.Minute},
+		{"update period", func(feed *RSSFeed) {
+			feed.Channel.UpdatePeriod = "daily"
+			feed.Channel.UpdateFrequency = "4"
+		}, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		var feed RSSFeed
+		tt.setup(&feed)
+		if got := fetchInterval(&feed); got != tt.want {
+			t.Errorf("%s: fetchInterval = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextFetchTime(t *testing.T) {
+	// 2024-01-05 is a Friday
+	now := time.Date(2024, 1, 5, 21, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		setup func(feed *RSSFeed)
+		want  time.Time
+	}{
+		{"no skips", func(feed *RSSFeed) {}, now.Add(time.Hour)},
+		{"skip hours", func(feed *RSSFeed) {
+			feed.Channel.SkipHours.Hours = []string{"22", "23"}
+		}, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"24 is midnight", func(feed *RSSFeed) {
+			feed.Channel.SkipHours.Hours = []string{"22", "23", "24"}
+		}, time.Date(2024, 1, 6, 1, 0, 0, 0, time.UTC)},
+		{"skip days", func(feed *RSSFeed) {
+			feed.Channel.SkipDays.Days = []string{"Friday", "Saturday", "sunday"}
+		}, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		var feed RSSFeed
+		tt.setup(&feed)
+		if got := nextFetchTime(&feed, now, time.Hour); !got.Equal(tt.want) {
+			t.Errorf("%s: nextFetchTime = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+type searchQuery struct {
+	Text   string
+	Feed   sql.NullString
+	Before sql.NullTime
+	After  sql.NullTime
+}
+
+// parseSearchQuery extracts the feed:, before: and after: qualifiers of a
+// query, leaving the rest for websearch_to_tsquery, which understands
+// "quoted phrases", OR and -excluded words.
+func parseSearchQuery(query string) (searchQuery, error) {
+	var parsed searchQuery
+	var words []string
+
+	for _, token := range splitQuoted(query) {
+		name, value, found := strings.Cut(token, ":")
+		if !found || strings.HasPrefix(token, `"`) {
+			words = append(words, token)
+			continue
+		}
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(name) {
+		case "feed":
+			parsed.Feed = sql.NullString{String: value, Valid: true}
+		case "before", "after":
+			t, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return searchQuery{}, fmt.Errorf("invalid date for %s: %s (expected YYYY-MM-DD)", name, value)
+			}
+			if strings.ToLower(name) == "before" {
+				parsed.Before = sql.NullTime{Time: t, Valid: true}
+			} else {
+				parsed.After = sql.NullTime{Time: t, Valid: true}
+			}
+		default:
+			words = append(words, token)
+		}
+	}
+
+	parsed.Text = strings.Join(words, " ")
+	return parsed, nil
+}
+
+// splitQuoted splits s on whitespace, except inside double quotes.
+func splitQuoted(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+func Search(s *state.State, cmd Command, user database.User) error {
+	args, flags, err := parseArgs(cmd.Args)
+	if err != nil || len(args) == 0 {
+		return fmt.Errorf(`usage: %s <query> [--limit N], e.g. %s '"rust async" feed:lobsters after:2024-01-01'`, cmd.Name, cmd.Name)
+	}
+
+	limit, err := intFlag(flags, "limit", 10)
+	if err != nil {
+		return err
+	}
+
+	query, err := parseSearchQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(query.Text) == "" {
+		return fmt.Errorf("nothing to search for")
+	}
+
+	results, err := s.Db.SearchPosts(context.Background(), database.SearchPostsParams{
+		Query:      query.Text,
+		UserID:     uuid.NullUUID{UUID: user.ID, Valid: true},
+		Feed:       query.Feed,
+		Before:     query.Before,
+		After:      query.After,
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't search posts: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No posts found.")
+		return nil
+	}
+	for _, result := range results {
+		fmt.Printf("* ID:            %s\n", result.ID)
+		fmt.Printf("* Title:         %s\n", result.Title.String)
+		fmt.Printf("* Feed:          %s\n", result.FeedName)
+		fmt.Printf("* URL:           %s\n", result.Url.String)
+		fmt.Printf("* Published:     %s\n", result.PublishedAt.Format(time.RFC1123))
+		fmt.Printf("* Match:         %s\n", strings.Join(strings.Fields(htmlToText(result.Snippet)), " "))
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	date := func(s string) sql.NullTime {
+		t, _ := time.Parse("2006-01-02", s)
+		return sql.NullTime{Time: t, Valid: true}
+	}
+
+	tests := []struct {
+		query string
+		want  searchQuery
+	}{
+		{"go generics", searchQuery{Text: "go generics"}},
+		{`"go generics" -rust`, searchQuery{Text: `"go generics" -rust`}},
+		{`feed:"Go Blog" release`, searchQuery{Text: "release", Feed: sql.NullString{String: "Go Blog", Valid: true}}},
+		{"after:2024-01-01 before:2024-02-01 postgres", searchQuery{Text: "postgres", After: date("2024-01-01"), Before: date("2024-02-01")}},
+		{`"time: now" https://example.com`, searchQuery{Text: `"time: now" https://example.com`}},
+	}
+	for _, tt := range tests {
+		got, err := parseSearchQuery(tt.query)
+		if err != nil {
+			t.Errorf("parseSearchQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := parseSearchQuery("before:yesterday"); err == nil {
+		t.Error(`parseSearchQuery("before:yesterday") should fail`)
+	}
+}
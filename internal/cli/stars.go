@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+	"github.com/google/uuid"
+)
+
+func Star(s *state.State, cmd Command, user database.User) error {
+	args, flags, err := parseArgs(cmd.Args)
+	if err != nil || len(args) != 1 {
+		return fmt.Errorf("usage: %s <post_id> [--note text]", cmd.Name)
+	}
+
+	postID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %s", args[0])
+	}
+	post, err := s.Db.GetPost(context.Background(), postID)
+	if err != nil {
+		return fmt.Errorf("couldn't get post: %w", err)
+	}
+
+	note, hasNote := flags["note"]
+	_, err = s.Db.StarPost(context.Background(), database.StarPostParams{
+		UserID:    user.ID,
+		PostID:    post.ID,
+		CreatedAt: time.Now().UTC(),
+		Note:      sql.NullString{String: note, Valid: hasNote},
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't star post: %w", err)
+	}
+
+	fmt.Printf("Starred %s\n", post.Title.String)
+	return nil
+}
+
+func Unstar(s *state.State, cmd Command, user database.User) error {
+	if len(cmd.Args) != 1 {
+		return fmt.Errorf("usage: %s <post_id>", cmd.Name)
+	}
+
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %s", cmd.Args[0])
+	}
+
+	unstarred, err := s.Db.UnstarPost(context.Background(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't unstar post: %w", err)
+	}
+	if unstarred == 0 {
+		fmt.Printf("%s was not starred\n", postID)
+		return nil
+	}
+	fmt.Printf("Unstarred %s\n", postID)
+	return nil
+}
+
+// Starred lists the starred posts, or writes them as a Markdown reading list
+// with "starred export [file]".
+func Starred(s *state.State, cmd Command, user database.User) error {
+	export := len(cmd.Args) > 0 && cmd.Args[0] == "export"
+	if (!export && len(cmd.Args) > 0) || len(cmd.Args) > 2 {
+		return fmt.Errorf("usage: %s [export [file.md]]", cmd.Name)
+	}
+
+	starred, err := s.Db.GetStarredPostsForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get starred posts: %w", err)
+	}
+
+	if export {
+		data := []byte(readingList(user, starred))
+		if len(cmd.Args) == 1 {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(cmd.Args[1], data, 0644); err != nil {
+			return fmt.Errorf("couldn't write %s: %w", cmd.Args[1], err)
+		}
+		fmt.Printf("Exported %d starred posts to %s\n", len(starred), cmd.Args[1])
+		return nil
+	}
+
+	if len(starred) == 0 {
+		fmt.Println("No starred posts.")
+		return nil
+	}
+	for _, post := range starred {
+		fmt.Printf("* ID:            %s\n", post.ID)
+		fmt.Printf("* Title:         %s\n", post.Title.String)
+		fmt.Printf("* Feed:          %s\n", post.FeedName)
+		fmt.Printf("* URL:           %s\n", post.Url.String)
+		fmt.Printf("* Starred:       %s\n", post.StarredAt.Format(time.RFC1123))
+		if post.Note.Valid {
+			fmt.Printf("* Note:          %s\n", post.Note.String)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func readingList(user database.User, starred []database.GetStarredPostsForUserRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s's reading list\n\n", user.Name)
+	for _, post := range starred {
+		title := strings.TrimSpace(post.Title.String)
+		if title == "" {
+			title = post.Url.String
+		}
+		title = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(title)
+
+		if post.Url.Valid {
+			fmt.Fprintf(&b, "- [%s](%s)", title, post.Url.String)
+		} else {
+			fmt.Fprintf(&b, "- %s", title)
+		}
+		fmt.Fprintf(&b, " — %s, %s\n", post.FeedName, post.PublishedAt.Format("2006-01-02"))
+		if post.Note.Valid && post.Note.String != "" {
+			for _, line := range strings.Split(post.Note.String, "\n") {
+				fmt.Fprintf(&b, "  > %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	blockTagRegexp  = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/ul|/ol|/h[1-6]|/blockquote|/pre|/tr)\s*/?>`)
+	itemTagRegexp   = regexp.MustCompile(`(?i)<\s*li[^>]*>`)
+	dropTagRegexp   = regexp.MustCompile(`(?is)<\s*(script|style)[^>]*>.*?<\s*/\s*(script|style)\s*>`)
+	anyTagRegexp    = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLineRegexp = regexp.MustCompile(`\n\s*\n\s*\n+`)
+	spacesRegexp    = regexp.MustCompile(`[ \t]+`)
+)
+
+// htmlToText turns the html of a post into something readable in a terminal:
+// block elements become line breaks, every other tag is dropped and entities
+// are decoded.
+func htmlToText(s string) string {
+	s = dropTagRegexp.ReplaceAllString(s, "")
+	s = blockTagRegexp.ReplaceAllString(s, "\n")
+	s = itemTagRegexp.ReplaceAllString(s, "\n- ")
+	s = anyTagRegexp.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = spacesRegexp.ReplaceAllString(s, " ")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	s = strings.Join(lines, "\n")
+	s = blankLineRegexp.ReplaceAllString(s, "\n\n")
+
+	return strings.TrimSpace(s)
+}
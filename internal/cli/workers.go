@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/state"
+)
+
+// hostLimiter caps how many feeds of the same host are fetched at once, so
+// that a domain hosting many of our feeds doesn't get hammered by the workers.
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// acquire blocks until a slot for host is free and returns the function
+// releasing it.
+func (l *hostLimiter) acquire(host string) func() {
+	l.mu.Lock()
+	slot, ok := l.slots[host]
+	if !ok {
+		slot = make(chan struct{}, l.limit)
+		l.slots[host] = slot
+	}
+	l.mu.Unlock()
+
+	slot <- struct{}{}
+	return func() { <-slot }
+}
+
+func feedHost(feed database.Feed) string {
+	u, err := url.Parse(feed.Url.String)
+	if err != nil {
+		return feed.Url.String
+	}
+	return u.Hostname()
+}
+
+// scrapeBatch claims the batchSize stalest feeds and scrapes them with a pool
+// of workers goroutines, returning once the whole batch is done.
+//
+// The claim is a single UPDATE ... FOR UPDATE SKIP LOCKED, so several agg
+// processes sharing the database never pick the same feed. Claimed feeds are
+// leased until released, or until the lease expires if the process dies. The
+// claimed_until of a lease identifies it: the lease is renewed once the
+// worker gets a host slot, so that waiting for one doesn't use it up, and the
+// feed is skipped if another process claimed it again in the meantime.
+func scrapeBatch(s *state.State, batchSize, workers int, lease time.Duration, limiter *hostLimiter) error {
+	feeds, err := s.Db.ClaimFeedsToFetch(context.Background(), database.ClaimFeedsToFetchParams{
+		LeaseSeconds: int32(lease.Seconds()),
+		MaxFeeds:     int32(batchSize),
+	})
+	if err != nil {
+		return err
+	}
+
+	queue := make(chan database.Feed)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for feed := range queue {
+				release := limiter.acquire(feedHost(feed))
+				claimedUntil, err := s.Db.RenewFeedLease(context.Background(), database.RenewFeedLeaseParams{
+					LeaseSeconds: int32(lease.Seconds()),
+					ID:           feed.ID,
+					ClaimedUntil: feed.ClaimedUntil,
+				})
+				if errors.Is(err, sql.ErrNoRows) {
+					log.Printf("lease of feed %s expired and it was claimed again, skipping it", feed.Name)
+				} else if err != nil {
+					log.Printf("failed to renew lease of feed %s: %v", feed.Name, err)
+				}
+				if err != nil {
+					release()
+					continue
+				}
+
+				if err := scrapeFeed(s, feed); err != nil {
+					log.Printf("failed to scrape feed %s: %v", feed.Name, err)
+				}
+				release()
+
+				err = s.Db.ReleaseFeed(context.Background(), database.ReleaseFeedParams{
+					ID:           feed.ID,
+					ClaimedUntil: claimedUntil,
+				})
+				if err != nil {
+					log.Printf("failed to release feed %s: %v", feed.Name, err)
+				}
+			}
+		}()
+	}
+
+	for _, feed := range feeds {
+		queue <- feed
+	}
+	close(queue)
+	wg.Wait()
+
+	return nil
+}
@@ -13,6 +13,27 @@ const configFileName = ".gatorconfig.json"
 type Config struct {
 	DbURL           string `json:"db_url"`
 	CurrentUserName string `json:"current_user_name"`
+	DownloadDir     string `json:"download_dir,omitempty"`
+	NotifyCommand   string `json:"notify_command,omitempty"`
+
+	// default retention policy, see the retention command for per-feed ones
+	RetentionMaxAge   string `json:"retention_max_age,omitempty"`
+	RetentionKeepLast int    `json:"retention_keep_last,omitempty"`
+}
+
+// GetDownloadDir returns where the download command saves media files,
+// defaulting to ~/Downloads/gator when download_dir is not set.
+func (c *Config) GetDownloadDir() (string, error) {
+	if c.DownloadDir != "" {
+		return c.DownloadDir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, "Downloads", "gator"), nil
 }
 
 func (c *Config) SetUser(name string) error {
@@ -20,18 +20,20 @@ WITH inserted_feed_follow AS (
     created_at,
     updated_at,
     user_id,
-    feed_id
+    feed_id,
+    category_id
   ) VALUES (  
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
-RETURNING id, created_at, updated_at, user_id, feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id
 )
 
-SELECT  inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id,
+SELECT  inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id, inserted_feed_follow.category_id,
 feeds.name AS feed_name,
 users.name AS user_name
 FROM inserted_feed_follow
@@ -42,21 +44,23 @@ ON inserted_feed_follow.user_id = users.id
 `
 
 type CreateFeedFollowParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.NullUUID
-	FeedID    uuid.NullUUID
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.NullUUID
+	FeedID     uuid.NullUUID
+	CategoryID uuid.NullUUID
 }
 
 type CreateFeedFollowRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.NullUUID
-	FeedID    uuid.NullUUID
-	FeedName  string
-	UserName  string
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.NullUUID
+	FeedID     uuid.NullUUID
+	CategoryID uuid.NullUUID
+	FeedName   string
+	UserName   string
 }
 
 func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
@@ -66,6 +70,7 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		arg.UpdatedAt,
 		arg.UserID,
 		arg.FeedID,
+		arg.CategoryID,
 	)
 	var i CreateFeedFollowRow
 	err := row.Scan(
@@ -74,6 +79,7 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.CategoryID,
 		&i.FeedName,
 		&i.UserName,
 	)
@@ -97,21 +103,27 @@ func (q *Queries) DeleteFeedFollowByUserAndFeedUrl(ctx context.Context, arg Dele
 }
 
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
-SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feeds.name AS feed_name, users.name AS user_name
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.category_id, feeds.name AS feed_name, users.name AS user_name,
+feeds.url AS feed_url, categories.name AS category_name
 FROM feed_follows
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
 INNER JOIN users ON feed_follows.user_id = users.id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
 WHERE feed_follows.user_id = $1
+ORDER BY categories.name NULLS FIRST, feeds.name
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.NullUUID
-	FeedID    uuid.NullUUID
-	FeedName  string
-	UserName  string
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uuid.NullUUID
+	FeedID       uuid.NullUUID
+	CategoryID   uuid.NullUUID
+	FeedName     string
+	UserName     string
+	FeedUrl      sql.NullString
+	CategoryName sql.NullString
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetFeedFollowsForUserRow, error) {
@@ -129,8 +141,11 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUI
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.CategoryID,
 			&i.FeedName,
 			&i.UserName,
+			&i.FeedUrl,
+			&i.CategoryName,
 		); err != nil {
 			return nil, err
 		}
@@ -144,3 +159,41 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUI
 	}
 	return items, nil
 }
+
+const moveFeedFollows = `-- name: MoveFeedFollows :exec
+UPDATE feed_follows
+SET feed_id = $1, updated_at = now()
+WHERE feed_follows.feed_id = $2
+AND feed_follows.user_id NOT IN (
+  SELECT target_follows.user_id FROM feed_follows AS target_follows
+  WHERE target_follows.feed_id = $1
+)
+`
+
+type MoveFeedFollowsParams struct {
+	TargetID uuid.NullUUID
+	SourceID uuid.NullUUID
+}
+
+func (q *Queries) MoveFeedFollows(ctx context.Context, arg MoveFeedFollowsParams) error {
+	_, err := q.db.ExecContext(ctx, moveFeedFollows, arg.TargetID, arg.SourceID)
+	return err
+}
+
+const setFeedFollowCategory = `-- name: SetFeedFollowCategory :exec
+UPDATE feed_follows
+SET category_id = $3, updated_at = now()
+WHERE feed_follows.user_id = $1
+AND feed_follows.feed_id = $2
+`
+
+type SetFeedFollowCategoryParams struct {
+	UserID     uuid.NullUUID
+	FeedID     uuid.NullUUID
+	CategoryID uuid.NullUUID
+}
+
+func (q *Queries) SetFeedFollowCategory(ctx context.Context, arg SetFeedFollowCategoryParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFollowCategory, arg.UserID, arg.FeedID, arg.CategoryID)
+	return err
+}
@@ -13,6 +13,68 @@ import (
 	"github.com/google/uuid"
 )
 
+const claimFeedsToFetch = `-- name: ClaimFeedsToFetch :many
+UPDATE feeds
+SET updated_at = now(),
+    claimed_until = now() + ($1::int * interval '1 second')
+WHERE feeds.id IN (
+  SELECT id FROM feeds
+  WHERE (feeds.claimed_until IS NULL OR feeds.claimed_until < now())
+  AND feeds.disabled_at IS NULL
+  AND (feeds.next_fetch_at IS NULL OR feeds.next_fetch_at <= now())
+  ORDER BY feeds.last_fetched_at ASC NULLS FIRST
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, claimed_until, consecutive_failures, last_success_at, next_fetch_at, disabled_at, fetch_interval_seconds, retention_max_age_seconds, retention_keep_last
+`
+
+type ClaimFeedsToFetchParams struct {
+	LeaseSeconds int32
+	MaxFeeds     int32
+}
+
+func (q *Queries) ClaimFeedsToFetch(ctx context.Context, arg ClaimFeedsToFetchParams) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, claimFeedsToFetch, arg.LeaseSeconds, arg.MaxFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.ClaimedUntil,
+			&i.ConsecutiveFailures,
+			&i.LastSuccessAt,
+			&i.NextFetchAt,
+			&i.DisabledAt,
+			&i.FetchIntervalSeconds,
+			&i.RetentionMaxAgeSeconds,
+			&i.RetentionKeepLast,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
 VALUES (
@@ -23,7 +85,7 @@ VALUES (
     $5,
     $6
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, claimed_until, consecutive_failures, last_success_at, next_fetch_at, disabled_at, fetch_interval_seconds, retention_max_age_seconds, retention_keep_last
 `
 
 type CreateFeedParams struct {
@@ -53,12 +115,78 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ClaimedUntil,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepLast,
+	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE feeds.id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const disableFeed = `-- name: DisableFeed :exec
+UPDATE feeds
+SET updated_at = now(), disabled_at = now()
+WHERE feeds.id = $1
+`
+
+func (q *Queries) DisableFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, disableFeed, id)
+	return err
+}
+
+const enableFeed = `-- name: EnableFeed :one
+UPDATE feeds
+SET updated_at = now(),
+    disabled_at = NULL,
+    next_fetch_at = NULL,
+    consecutive_failures = 0
+WHERE feeds.url = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, claimed_until, consecutive_failures, last_success_at, next_fetch_at, disabled_at, fetch_interval_seconds, retention_max_age_seconds, retention_keep_last
+`
+
+func (q *Queries) EnableFeed(ctx context.Context, url sql.NullString) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, enableFeed, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ClaimedUntil,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepLast,
 	)
 	return i, err
 }
 
 const getFeed = `-- name: GetFeed :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, claimed_until, consecutive_failures, last_success_at, next_fetch_at, disabled_at, fetch_interval_seconds, retention_max_age_seconds, retention_keep_last FROM feeds
 WHERE feeds.id = $1
 `
 
@@ -73,12 +201,22 @@ func (q *Queries) GetFeed(ctx context.Context, id uuid.UUID) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ClaimedUntil,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepLast,
 	)
 	return i, err
 }
 
 const getFeedByUrl = `-- name: GetFeedByUrl :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, claimed_until, consecutive_failures, last_success_at, next_fetch_at, disabled_at, fetch_interval_seconds, retention_max_age_seconds, retention_keep_last FROM feeds
 WHERE feeds.url = $1
 `
 
@@ -93,30 +231,50 @@ func (q *Queries) GetFeedByUrl(ctx context.Context, url sql.NullString) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ClaimedUntil,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.DisabledAt,
+		&i.FetchIntervalSeconds,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepLast,
 	)
 	return i, err
 }
 
 const getFeeds = `-- name: GetFeeds :many
-SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.name, url, user_id, last_fetched_at, users.id, users.created_at, users.updated_at, users.name, users.name as user_name FROM feeds
+SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.name, url, user_id, last_fetched_at, etag, last_modified, claimed_until, consecutive_failures, last_success_at, next_fetch_at, disabled_at, fetch_interval_seconds, retention_max_age_seconds, retention_keep_last, users.id, users.created_at, users.updated_at, users.name, users.name as user_name FROM feeds
 INNER JOIN users
 ON feeds.user_id = users.id
 ORDER By feeds.created_at DESC
 `
 
 type GetFeedsRow struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           sql.NullString
-	UserID        uuid.NullUUID
-	LastFetchedAt sql.NullTime
-	ID_2          uuid.UUID
-	CreatedAt_2   time.Time
-	UpdatedAt_2   time.Time
-	Name_2        string
-	UserName      string
+	ID                     uuid.UUID
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	Name                   string
+	Url                    sql.NullString
+	UserID                 uuid.NullUUID
+	LastFetchedAt          sql.NullTime
+	Etag                   sql.NullString
+	LastModified           sql.NullString
+	ClaimedUntil           sql.NullTime
+	ConsecutiveFailures    int32
+	LastSuccessAt          sql.NullTime
+	NextFetchAt            sql.NullTime
+	DisabledAt             sql.NullTime
+	FetchIntervalSeconds   sql.NullInt32
+	RetentionMaxAgeSeconds sql.NullInt32
+	RetentionKeepLast      sql.NullInt32
+	ID_2                   uuid.UUID
+	CreatedAt_2            time.Time
+	UpdatedAt_2            time.Time
+	Name_2                 string
+	UserName               string
 }
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
@@ -136,6 +294,16 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.ClaimedUntil,
+			&i.ConsecutiveFailures,
+			&i.LastSuccessAt,
+			&i.NextFetchAt,
+			&i.DisabledAt,
+			&i.FetchIntervalSeconds,
+			&i.RetentionMaxAgeSeconds,
+			&i.RetentionKeepLast,
 			&i.ID_2,
 			&i.CreatedAt_2,
 			&i.UpdatedAt_2,
@@ -155,34 +323,131 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
-ORDER BY feeds.last_fetched_at ASC NULLS FIRST
-LIMIT 1
+const markFeedFetchFailed = `-- name: MarkFeedFetchFailed :exec
+UPDATE feeds
+SET updated_at = now(),
+    last_fetched_at = now(),
+    consecutive_failures = feeds.consecutive_failures + 1,
+    next_fetch_at = now() + ($1::int * interval '1 second')
+WHERE feeds.id = $2
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-		&i.LastFetchedAt,
-	)
-	return i, err
+type MarkFeedFetchFailedParams struct {
+	RetryInSeconds int32
+	ID             uuid.UUID
+}
+
+func (q *Queries) MarkFeedFetchFailed(ctx context.Context, arg MarkFeedFetchFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetchFailed, arg.RetryInSeconds, arg.ID)
+	return err
+}
+
+const markFeedFetchSucceeded = `-- name: MarkFeedFetchSucceeded :exec
+UPDATE feeds
+SET updated_at = now(),
+    last_fetched_at = now(),
+    last_success_at = now(),
+    consecutive_failures = 0,
+    fetch_interval_seconds = $1,
+    next_fetch_at = now() + ($2::int * interval '1 second')
+WHERE feeds.id = $3
+`
+
+type MarkFeedFetchSucceededParams struct {
+	FetchIntervalSeconds sql.NullInt32
+	NextFetchInSeconds   int32
+	ID                   uuid.UUID
+}
+
+func (q *Queries) MarkFeedFetchSucceeded(ctx context.Context, arg MarkFeedFetchSucceededParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetchSucceeded, arg.FetchIntervalSeconds, arg.NextFetchInSeconds, arg.ID)
+	return err
+}
+
+const releaseFeed = `-- name: ReleaseFeed :exec
+UPDATE feeds
+SET claimed_until = NULL
+WHERE feeds.id = $1
+AND feeds.claimed_until = $2
+`
+
+type ReleaseFeedParams struct {
+	ID           uuid.UUID
+	ClaimedUntil sql.NullTime
+}
+
+func (q *Queries) ReleaseFeed(ctx context.Context, arg ReleaseFeedParams) error {
+	_, err := q.db.ExecContext(ctx, releaseFeed, arg.ID, arg.ClaimedUntil)
+	return err
+}
+
+const renewFeedLease = `-- name: RenewFeedLease :one
+UPDATE feeds
+SET claimed_until = now() + ($1::int * interval '1 second')
+WHERE feeds.id = $2
+AND feeds.claimed_until = $3
+RETURNING feeds.claimed_until
+`
+
+type RenewFeedLeaseParams struct {
+	LeaseSeconds int32
+	ID           uuid.UUID
+	ClaimedUntil sql.NullTime
+}
+
+func (q *Queries) RenewFeedLease(ctx context.Context, arg RenewFeedLeaseParams) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, renewFeedLease, arg.LeaseSeconds, arg.ID, arg.ClaimedUntil)
+	var claimed_until sql.NullTime
+	err := row.Scan(&claimed_until)
+	return claimed_until, err
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :exec
+UPDATE feeds
+SET retention_max_age_seconds = $2, retention_keep_last = $3
+WHERE feeds.id = $1
+`
+
+type SetFeedRetentionParams struct {
+	ID                     uuid.UUID
+	RetentionMaxAgeSeconds sql.NullInt32
+	RetentionKeepLast      sql.NullInt32
+}
+
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRetention, arg.ID, arg.RetentionMaxAgeSeconds, arg.RetentionKeepLast)
+	return err
+}
+
+const updateFeedCacheHeaders = `-- name: UpdateFeedCacheHeaders :exec
+UPDATE feeds
+SET etag = $2, last_modified = $3
+WHERE feeds.id = $1
+`
+
+type UpdateFeedCacheHeadersParams struct {
+	ID           uuid.UUID
+	Etag         sql.NullString
+	LastModified sql.NullString
 }
 
-const markFeedFetched = `-- name: MarkFeedFetched :exec
-UPDATE feeds 
-SET updated_at = now(), last_fetched_at = now()
+func (q *Queries) UpdateFeedCacheHeaders(ctx context.Context, arg UpdateFeedCacheHeadersParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCacheHeaders, arg.ID, arg.Etag, arg.LastModified)
+	return err
+}
+
+const updateFeedUrl = `-- name: UpdateFeedUrl :exec
+UPDATE feeds
+SET updated_at = now(), url = $2
 WHERE feeds.id = $1
 `
 
-func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+type UpdateFeedUrlParams struct {
+	ID  uuid.UUID
+	Url sql.NullString
+}
+
+func (q *Queries) UpdateFeedUrl(ctx context.Context, arg UpdateFeedUrlParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedUrl, arg.ID, arg.Url)
 	return err
 }
@@ -11,33 +11,149 @@ import (
 	"github.com/google/uuid"
 )
 
+type Category struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+}
+
 type Feed struct {
+	ID                     uuid.UUID
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	Name                   string
+	Url                    sql.NullString
+	UserID                 uuid.NullUUID
+	LastFetchedAt          sql.NullTime
+	Etag                   sql.NullString
+	LastModified           sql.NullString
+	ClaimedUntil           sql.NullTime
+	ConsecutiveFailures    int32
+	LastSuccessAt          sql.NullTime
+	NextFetchAt            sql.NullTime
+	DisabledAt             sql.NullTime
+	FetchIntervalSeconds   sql.NullInt32
+	RetentionMaxAgeSeconds sql.NullInt32
+	RetentionKeepLast      sql.NullInt32
+}
+
+type FeedFetchLog struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	FeedID     uuid.UUID
+	StatusCode sql.NullInt32
+	Error      sql.NullString
+	DurationMs int32
+	ItemCount  int32
+}
+
+type FeedFollow struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.NullUUID
+	FeedID     uuid.NullUUID
+	CategoryID uuid.NullUUID
+}
+
+type FeedUrlChange struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	FeedID     uuid.UUID
+	OldUrl     string
+	NewUrl     string
+	StatusCode int32
+	Merged     bool
+}
+
+type IngestRule struct {
 	ID            uuid.UUID
 	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	UserID        uuid.UUID
 	Name          string
-	Url           sql.NullString
-	UserID        uuid.NullUUID
-	LastFetchedAt sql.NullTime
+	Position      int32
+	FeedID        uuid.NullUUID
+	CategoryID    uuid.NullUUID
+	TitlePattern  sql.NullString
+	MinAgeSeconds sql.NullInt32
+	MaxAgeSeconds sql.NullInt32
+	Action        string
+	Tag           sql.NullString
+	Stop          bool
 }
 
-type FeedFollow struct {
+type Post struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PublishedAt     time.Time
+	Title           sql.NullString
+	Url             sql.NullString
+	Description     sql.NullString
+	FeedID          uuid.NullUUID
+	Content         sql.NullString
+	Guid            string
+	ContentHash     sql.NullString
+	SourceUpdatedAt sql.NullTime
+	EditedAt        sql.NullTime
+	SearchVector    interface{}
+	Author          sql.NullString
+}
+
+type PostEnclosure struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	UserID    uuid.NullUUID
+	PostID    uuid.UUID
+	Url       string
+	MimeType  sql.NullString
+	Length    sql.NullInt64
+	Duration  sql.NullString
+	Episode   sql.NullString
+	ImageUrl  sql.NullString
+}
+
+type PostFilter struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
 	FeedID    uuid.NullUUID
+	Field     string
+	Pattern   string
+	IsRegex   bool
+	Action    string
 }
 
-type Post struct {
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostRevision struct {
 	ID          uuid.UUID
 	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	PublishedAt time.Time
+	PostID      uuid.UUID
 	Title       sql.NullString
 	Url         sql.NullString
 	Description sql.NullString
-	FeedID      uuid.NullUUID
+	Content     sql.NullString
+}
+
+type PostTag struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Tag       string
+	CreatedAt time.Time
+}
+
+type StarredPost struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+	Note      sql.NullString
 }
 
 type User struct {
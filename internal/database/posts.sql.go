@@ -13,8 +13,35 @@ import (
 	"github.com/google/uuid"
 )
 
+const adoptLegacyPostGuid = `-- name: AdoptLegacyPostGuid :execrows
+UPDATE posts
+SET guid = $1
+WHERE posts.feed_id = $2
+AND posts.url = $3
+AND posts.guid = posts.url
+AND NOT EXISTS (
+  SELECT 1 FROM posts AS existing
+  WHERE existing.feed_id = $2
+  AND existing.guid = $1
+)
+`
+
+type AdoptLegacyPostGuidParams struct {
+	Guid   string
+	FeedID uuid.NullUUID
+	Url    sql.NullString
+}
+
+func (q *Queries) AdoptLegacyPostGuid(ctx context.Context, arg AdoptLegacyPostGuidParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, adoptLegacyPostGuid, arg.Guid, arg.FeedID, arg.Url)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts(id, created_at, updated_at, published_at, title, url, description, feed_id)
+INSERT INTO posts(id, created_at, updated_at, published_at, title, url, description, feed_id, content, guid, content_hash, source_updated_at, author)
 VALUES (
     $1,
     $2,
@@ -23,20 +50,31 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 )
-RETURNING id, created_at, updated_at, published_at, title, url, description, feed_id
+ON CONFLICT (feed_id, guid) DO NOTHING
+RETURNING id, created_at, updated_at, published_at, title, url, description, feed_id, content, guid, content_hash, source_updated_at, edited_at, search_vector, author
 `
 
 type CreatePostParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	PublishedAt time.Time
-	Title       sql.NullString
-	Url         sql.NullString
-	Description sql.NullString
-	FeedID      uuid.NullUUID
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PublishedAt     time.Time
+	Title           sql.NullString
+	Url             sql.NullString
+	Description     sql.NullString
+	FeedID          uuid.NullUUID
+	Content         sql.NullString
+	Guid            string
+	ContentHash     sql.NullString
+	SourceUpdatedAt sql.NullTime
+	Author          sql.NullString
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -49,7 +87,74 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Url,
 		arg.Description,
 		arg.FeedID,
+		arg.Content,
+		arg.Guid,
+		arg.ContentHash,
+		arg.SourceUpdatedAt,
+		arg.Author,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.FeedID,
+		&i.Content,
+		&i.Guid,
+		&i.ContentHash,
+		&i.SourceUpdatedAt,
+		&i.EditedAt,
+		&i.SearchVector,
+		&i.Author,
+	)
+	return i, err
+}
+
+const getPost = `-- name: GetPost :one
+SELECT id, created_at, updated_at, published_at, title, url, description, feed_id, content, guid, content_hash, source_updated_at, edited_at, search_vector, author FROM posts
+WHERE posts.id = $1
+`
+
+func (q *Queries) GetPost(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPost, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.FeedID,
+		&i.Content,
+		&i.Guid,
+		&i.ContentHash,
+		&i.SourceUpdatedAt,
+		&i.EditedAt,
+		&i.SearchVector,
+		&i.Author,
 	)
+	return i, err
+}
+
+const getPostByFeedAndGuid = `-- name: GetPostByFeedAndGuid :one
+SELECT id, created_at, updated_at, published_at, title, url, description, feed_id, content, guid, content_hash, source_updated_at, edited_at, search_vector, author FROM posts
+WHERE posts.feed_id = $1
+AND posts.guid = $2
+`
+
+type GetPostByFeedAndGuidParams struct {
+	FeedID uuid.NullUUID
+	Guid   string
+}
+
+func (q *Queries) GetPostByFeedAndGuid(ctx context.Context, arg GetPostByFeedAndGuidParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByFeedAndGuid, arg.FeedID, arg.Guid)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -60,34 +165,213 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Url,
 		&i.Description,
 		&i.FeedID,
+		&i.Content,
+		&i.Guid,
+		&i.ContentHash,
+		&i.SourceUpdatedAt,
+		&i.EditedAt,
+		&i.SearchVector,
+		&i.Author,
 	)
 	return i, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT id, created_at, updated_at, published_at, title, url, description, feed_id FROM posts
-ORDER BY posts.published_at 
-LIMIT $1
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.url, posts.description, posts.feed_id, posts.content, posts.guid, posts.content_hash, posts.source_updated_at, posts.edited_at, posts.search_vector, posts.author, coalesce(filters.highlighted, false)::boolean AS highlighted FROM posts
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+INNER JOIN feeds ON feeds.id = posts.feed_id
+CROSS JOIN LATERAL (
+  SELECT bool_or(post_filters.action = 'hide') AS hidden, bool_or(post_filters.action = 'highlight') AS highlighted
+  FROM post_filters
+  WHERE post_filters.user_id = feed_follows.user_id
+  AND (post_filters.feed_id IS NULL OR post_filters.feed_id = posts.feed_id)
+  AND EXISTS (
+    SELECT 1 FROM unnest(CASE post_filters.field
+      WHEN 'title' THEN ARRAY[posts.title]
+      WHEN 'description' THEN ARRAY[posts.description]
+      WHEN 'author' THEN ARRAY[posts.author]
+      ELSE ARRAY[posts.title, posts.description, posts.author]
+    END) AS field_value
+    WHERE CASE WHEN post_filters.is_regex THEN field_value ~* post_filters.pattern
+      ELSE strpos(lower(field_value), lower(post_filters.pattern)) > 0
+    END
+  )
+) AS filters
+WHERE feed_follows.user_id = $1
+AND ($2::boolean OR NOT coalesce(filters.hidden, false))
+AND ($3::text IS NULL OR feeds.name = $3 OR feeds.url = $3)
+AND ($4::text IS NULL OR feed_follows.category_id = (
+  SELECT categories.id FROM categories
+  WHERE categories.user_id = feed_follows.user_id
+  AND categories.name = $4
+))
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+AND ($6::timestamp IS NULL OR posts.published_at < $6)
+AND ($7::uuid IS NULL OR (posts.published_at, posts.id) < (
+  SELECT cursor_posts.published_at, cursor_posts.id FROM posts AS cursor_posts
+  WHERE cursor_posts.id = $7
+))
+AND ($8::boolean OR NOT EXISTS (
+  SELECT 1 FROM post_reads
+  WHERE post_reads.user_id = feed_follows.user_id
+  AND post_reads.post_id = posts.id
+))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT $9
+`
+
+type GetPostsForUserParams struct {
+	UserID        uuid.NullUUID
+	IncludeHidden bool
+	Feed          sql.NullString
+	Category      sql.NullString
+	Since         sql.NullTime
+	Until         sql.NullTime
+	BeforeID      uuid.NullUUID
+	IncludeRead   bool
+	MaxPosts      int32
+}
+
+type GetPostsForUserRow struct {
+	Post        Post
+	Highlighted bool
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.IncludeHidden,
+		arg.Feed,
+		arg.Category,
+		arg.Since,
+		arg.Until,
+		arg.BeforeID,
+		arg.IncludeRead,
+		arg.MaxPosts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.PublishedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.Guid,
+			&i.Post.ContentHash,
+			&i.Post.SourceUpdatedAt,
+			&i.Post.EditedAt,
+			&i.Post.SearchVector,
+			&i.Post.Author,
+			&i.Highlighted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveFeedPosts = `-- name: MoveFeedPosts :exec
+UPDATE posts
+SET feed_id = $1
+WHERE posts.feed_id = $2
+AND posts.guid NOT IN (
+  SELECT target_posts.guid FROM posts AS target_posts
+  WHERE target_posts.feed_id = $1
+)
+`
+
+type MoveFeedPostsParams struct {
+	TargetID uuid.NullUUID
+	SourceID uuid.NullUUID
+}
+
+func (q *Queries) MoveFeedPosts(ctx context.Context, arg MoveFeedPostsParams) error {
+	_, err := q.db.ExecContext(ctx, moveFeedPosts, arg.TargetID, arg.SourceID)
+	return err
+}
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT posts.id, posts.title, posts.url, posts.published_at, feeds.name AS feed_name,
+  ts_rank(posts.search_vector, query)::real AS rank,
+  ts_headline(
+    'english',
+    coalesce(nullif(posts.content, ''), nullif(posts.description, ''), posts.title, ''),
+    query,
+    'StartSel=**, StopSel=**, MaxFragments=2, MaxWords=20, MinWords=8'
+  )::text AS snippet
+FROM posts
+CROSS JOIN websearch_to_tsquery('english', $1) AS query
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE feed_follows.user_id = $2
+AND posts.search_vector @@ query
+AND ($3::text IS NULL OR feeds.name = $3 OR feeds.url = $3)
+AND ($4::timestamp IS NULL OR posts.published_at < $4)
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT $6
 `
 
-func (q *Queries) GetPostsForUser(ctx context.Context, limit int32) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, limit)
+type SearchPostsParams struct {
+	Query      string
+	UserID     uuid.NullUUID
+	Feed       sql.NullString
+	Before     sql.NullTime
+	After      sql.NullTime
+	MaxResults int32
+}
+
+type SearchPostsRow struct {
+	ID          uuid.UUID
+	Title       sql.NullString
+	Url         sql.NullString
+	PublishedAt time.Time
+	FeedName    string
+	Rank        float32
+	Snippet     string
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.UserID,
+		arg.Feed,
+		arg.Before,
+		arg.After,
+		arg.MaxResults,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Post
+	var items []SearchPostsRow
 	for rows.Next() {
-		var i Post
+		var i SearchPostsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.PublishedAt,
 			&i.Title,
 			&i.Url,
-			&i.Description,
-			&i.FeedID,
+			&i.PublishedAt,
+			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -101,3 +385,60 @@ func (q *Queries) GetPostsForUser(ctx context.Context, limit int32) ([]Post, err
 	}
 	return items, nil
 }
+
+const updatePost = `-- name: UpdatePost :one
+UPDATE posts
+SET title = $2,
+    url = $3,
+    description = $4,
+    content = $5,
+    content_hash = $6,
+    source_updated_at = $7,
+    updated_at = $8,
+    edited_at = $8
+WHERE posts.id = $1
+RETURNING id, created_at, updated_at, published_at, title, url, description, feed_id, content, guid, content_hash, source_updated_at, edited_at, search_vector, author
+`
+
+type UpdatePostParams struct {
+	ID              uuid.UUID
+	Title           sql.NullString
+	Url             sql.NullString
+	Description     sql.NullString
+	Content         sql.NullString
+	ContentHash     sql.NullString
+	SourceUpdatedAt sql.NullTime
+	UpdatedAt       time.Time
+}
+
+func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, updatePost,
+		arg.ID,
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		arg.Content,
+		arg.ContentHash,
+		arg.SourceUpdatedAt,
+		arg.UpdatedAt,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.FeedID,
+		&i.Content,
+		&i.Guid,
+		&i.ContentHash,
+		&i.SourceUpdatedAt,
+		&i.EditedAt,
+		&i.SearchVector,
+		&i.Author,
+	)
+	return i, err
+}
@@ -0,0 +1,277 @@
+// Package migrate applies the embedded goose migrations. It keeps track of
+// them in goose's own goose_db_version table, so databases migrated with the
+// goose CLI are picked up as they are.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/federicoReghini/gator/sql/schema"
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt sql.NullTime
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := fs.Glob(schema.FS, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, file := range files {
+		prefix, _, found := strings.Cut(file, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !found || err != nil {
+			return nil, fmt.Errorf("migration %s doesn't start with a version number", file)
+		}
+
+		data, err := fs.ReadFile(schema.FS, file)
+		if err != nil {
+			return nil, err
+		}
+		up, down, err := parseMigration(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", file, err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    strings.TrimSuffix(path.Base(file), ".sql"),
+			Up:      up,
+			Down:    down,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigration splits a goose migration into its Up and Down sections. Any
+// other goose annotation, such as StatementBegin or NO TRANSACTION, is
+// rejected rather than silently run the wrong way.
+func parseMigration(data string) (string, string, error) {
+	var up, down strings.Builder
+	var section *strings.Builder
+
+	for _, line := range strings.SplitAfter(data, "\n") {
+		switch annotation := strings.TrimSpace(line); {
+		case annotation == "-- +goose Up":
+			section = &up
+			continue
+		case annotation == "-- +goose Down":
+			section = &down
+			continue
+		case strings.HasPrefix(annotation, "-- +goose"):
+			return "", "", fmt.Errorf("unsupported annotation %q", annotation)
+		}
+		if section != nil {
+			section.WriteString(line)
+		}
+	}
+
+	if strings.TrimSpace(up.String()) == "" {
+		return "", "", fmt.Errorf("no -- +goose Up section")
+	}
+	return up.String(), down.String(), nil
+}
+
+// ensureVersionTable creates goose_db_version the way goose does, including
+// the version 0 row goose expects to find in it.
+func ensureVersionTable(ctx context.Context, db *sql.DB) error {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT to_regclass('goose_db_version') IS NOT NULL`).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `CREATE TABLE goose_db_version (
+  id SERIAL PRIMARY KEY,
+  version_id BIGINT NOT NULL,
+  is_applied BOOLEAN NOT NULL,
+  tstamp TIMESTAMP NULL DEFAULT now()
+)`)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO goose_db_version (version_id, is_applied) VALUES (0, true)`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applied returns when each applied version was applied.
+func applied(ctx context.Context, db *sql.DB) (map[int64]time.Time, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT to_regclass('goose_db_version') IS NOT NULL`).Scan(&exists)
+	if err != nil || !exists {
+		return map[int64]time.Time{}, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version_id, is_applied, coalesce(tstamp, now()) FROM goose_db_version ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var tstamp time.Time
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		// older goose versions record a rollback as a new row
+		if isApplied {
+			versions[version] = tstamp
+		} else {
+			delete(versions, version)
+		}
+	}
+	return versions, rows.Err()
+}
+
+// Check compares the applied versions with the embedded migrations. It
+// returns the migrations that still have to be applied and the applied
+// versions that no embedded migration knows about.
+func Check(ctx context.Context, db *sql.DB) ([]Migration, []int64, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, nil, err
+	}
+	versions, err := applied(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := versions[migration.Version]; ok {
+			delete(versions, migration.Version)
+		} else {
+			pending = append(pending, migration)
+		}
+	}
+
+	var unknown []int64
+	for version := range versions {
+		// goose records the empty database as version 0
+		if version != 0 {
+			unknown = append(unknown, version)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i] < unknown[j] })
+	return pending, unknown, nil
+}
+
+func Status(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i].Migration = migration
+		if appliedAt, ok := versions[migration.Version]; ok {
+			statuses[i].AppliedAt = sql.NullTime{Time: appliedAt, Valid: true}
+		}
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns the ones applied.
+func Up(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	if err := ensureVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+	statuses, err := Status(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, status := range statuses {
+		if status.AppliedAt.Valid {
+			continue
+		}
+		err := run(ctx, db, status.Up, `INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, true)`, status.Version)
+		if err != nil {
+			return done, fmt.Errorf("migration %s failed: %w", status.Name, err)
+		}
+		done = append(done, status.Migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the latest applied migration, returning false when there
+// is none.
+func Down(ctx context.Context, db *sql.DB) (Migration, bool, error) {
+	statuses, err := Status(ctx, db)
+	if err != nil {
+		return Migration{}, false, err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if !statuses[i].AppliedAt.Valid {
+			continue
+		}
+		migration := statuses[i].Migration
+		err := run(ctx, db, migration.Down, `DELETE FROM goose_db_version WHERE version_id = $1`, migration.Version)
+		if err != nil {
+			return Migration{}, false, fmt.Errorf("rollback of %s failed: %w", migration.Name, err)
+		}
+		return migration, true, nil
+	}
+	return Migration{}, false, nil
+}
+
+// run executes a migration section and records it in goose_db_version within
+// the same transaction.
+func run(ctx context.Context, db *sql.DB, statements, record string, version int64) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(statements) != "" {
+		if _, err := tx.ExecContext(ctx, statements); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
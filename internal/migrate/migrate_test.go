@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMigration(t *testing.T) {
+	up, down, err := parseMigration(`-- a comment before the sections
+-- +goose Up
+CREATE TABLE t (id INT);
+
+-- +goose Down
+DROP TABLE t;
+`)
+	if err != nil {
+		t.Fatalf("parseMigration: %v", err)
+	}
+	if up != "CREATE TABLE t (id INT);\n\n" {
+		t.Errorf("up = %q", up)
+	}
+	if down != "DROP TABLE t;\n" {
+		t.Errorf("down = %q", down)
+	}
+
+	for _, data := range []string{
+		"-- +goose Down\nDROP TABLE t;\n",
+		"-- +goose Up\n-- +goose StatementBegin\nCREATE FUNCTION f() ...;\n-- +goose StatementEnd\n",
+		"-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY i ON t (id);\n",
+	} {
+		if _, _, err := parseMigration(data); err == nil {
+			t.Errorf("parseMigration(%q) should fail", data)
+		}
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+	for i, migration := range migrations {
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			t.Errorf("%s isn't after %s", migration.Name, migrations[i-1].Name)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("%s has no Down section", migration.Name)
+		}
+	}
+}
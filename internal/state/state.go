@@ -1,11 +1,14 @@
 package state
 
 import (
+	"database/sql"
+
 	config "github.com/federicoReghini/gator/internal/config"
 	"github.com/federicoReghini/gator/internal/database"
 )
 
 type State struct {
-	Db  *database.Queries
-	Cfg *config.Config
+	Db   *database.Queries
+	Conn *sql.DB
+	Cfg  *config.Config
 }
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/federicoReghini/gator/internal/cli"
 	"github.com/federicoReghini/gator/internal/config"
 	"github.com/federicoReghini/gator/internal/database"
+	"github.com/federicoReghini/gator/internal/migrate"
 	"github.com/federicoReghini/gator/internal/state"
 	_ "github.com/lib/pq"
 	"os"
+	"strings"
 )
 
 func main() {
@@ -25,7 +28,25 @@ func main() {
 	cmds.Register("follow", cli.MiddlewareLoggedIn(cli.Follow))
 	cmds.Register("following", cli.MiddlewareLoggedIn(cli.Following))
 	cmds.Register("unfollow", cli.MiddlewareLoggedIn(cli.Unfollow))
-	cmds.Register("browse", cli.Browse)
+	cmds.Register("category", cli.MiddlewareLoggedIn(cli.Category))
+	cmds.Register("import", cli.MiddlewareLoggedIn(cli.Import))
+	cmds.Register("export", cli.MiddlewareLoggedIn(cli.Export))
+	cmds.Register("browse", cli.MiddlewareLoggedIn(cli.Browse))
+	cmds.Register("filter", cli.MiddlewareLoggedIn(cli.Filter))
+	cmds.Register("rule", cli.MiddlewareLoggedIn(cli.Rule))
+	cmds.Register("read", cli.MiddlewareLoggedIn(cli.Read))
+	cmds.Register("unread", cli.MiddlewareLoggedIn(cli.Unread))
+	cmds.Register("star", cli.MiddlewareLoggedIn(cli.Star))
+	cmds.Register("unstar", cli.MiddlewareLoggedIn(cli.Unstar))
+	cmds.Register("starred", cli.MiddlewareLoggedIn(cli.Starred))
+	cmds.Register("search", cli.MiddlewareLoggedIn(cli.Search))
+	cmds.Register("download", cli.Download)
+	cmds.Register("post", cli.Post)
+	cmds.Register("health", cli.Health)
+	cmds.Register("enable", cli.Enable)
+	cmds.Register("retention", cli.Retention)
+	cmds.Register("prune", cli.Prune)
+	cmds.Register("migrate", cli.Migrate)
 
 	if len(os.Args) < 2 {
 		fmt.Println("Not enough args, there must be at least 2 args")
@@ -39,11 +60,20 @@ func main() {
 		fmt.Println("db not connect ", err)
 	}
 
+	// every other command needs the schema this binary was built for
+	if os.Args[1] != "migrate" {
+		if err := checkSchema(db); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
 	dbQueries := database.New(db)
 
 	appState := &state.State{
-		Cfg: cfg,
-		Db:  dbQueries,
+		Cfg:  cfg,
+		Db:   dbQueries,
+		Conn: db,
 	}
 
 	// Run Command
@@ -60,3 +90,22 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func checkSchema(db *sql.DB) error {
+	pending, unknown, err := migrate.Check(context.Background(), db)
+	if err != nil {
+		return fmt.Errorf("couldn't check the database schema: %w", err)
+	}
+
+	switch {
+	case len(unknown) > 0:
+		return fmt.Errorf("the database has migrations %v applied that this gator doesn't know about, please upgrade gator", unknown)
+	case len(pending) > 0:
+		var names []string
+		for _, migration := range pending {
+			names = append(names, migration.Name)
+		}
+		return fmt.Errorf("the database is missing migrations %s, run `gator migrate up` first", strings.Join(names, ", "))
+	}
+	return nil
+}
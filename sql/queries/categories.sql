@@ -0,0 +1,26 @@
+-- name: UpsertCategory :one
+INSERT INTO categories (id, created_at, updated_at, user_id, name)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (user_id, name) DO UPDATE SET updated_at = categories.updated_at
+RETURNING *;
+
+-- name: GetCategoriesForUser :many
+SELECT * FROM categories
+WHERE categories.user_id = $1
+ORDER BY categories.name;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE categories.user_id = $1
+AND categories.name = $2;
+
+-- name: GetCategoryByName :one
+SELECT * FROM categories
+WHERE categories.user_id = $1
+AND categories.name = $2;
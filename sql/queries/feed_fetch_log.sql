@@ -0,0 +1,48 @@
+-- name: CreateFeedFetchLog :one
+INSERT INTO feed_fetch_log (id, created_at, feed_id, status_code, error, duration_ms, item_count)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetFeedHealth :many
+SELECT feeds.*,
+  COALESCE((
+    SELECT feed_fetch_log.status_code FROM feed_fetch_log
+    WHERE feed_fetch_log.feed_id = feeds.id
+    ORDER BY feed_fetch_log.created_at DESC
+    LIMIT 1
+  ), 0)::int AS last_status_code,
+  COALESCE((
+    SELECT feed_fetch_log.error FROM feed_fetch_log
+    WHERE feed_fetch_log.feed_id = feeds.id
+    ORDER BY feed_fetch_log.created_at DESC
+    LIMIT 1
+  ), '')::text AS last_error,
+  COALESCE((
+    SELECT AVG(feed_fetch_log.duration_ms) FROM feed_fetch_log
+    WHERE feed_fetch_log.feed_id = feeds.id
+    AND feed_fetch_log.created_at > sqlc.arg(slow_since)
+  ), 0)::int AS avg_duration_ms,
+  COALESCE((
+    SELECT MAX(posts.published_at) FROM posts
+    WHERE posts.feed_id = feeds.id
+  ), feeds.created_at)::timestamp AS latest_post_at
+FROM feeds
+ORDER BY feeds.name;
+
+-- name: DeleteFeedFetchLogsBefore :execrows
+DELETE FROM feed_fetch_log
+WHERE feed_fetch_log.created_at < sqlc.arg(before)
+AND feed_fetch_log.id <> (
+  SELECT latest.id FROM feed_fetch_log AS latest
+  WHERE latest.feed_id = feed_fetch_log.feed_id
+  ORDER BY latest.created_at DESC
+  LIMIT 1
+);
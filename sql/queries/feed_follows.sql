@@ -5,13 +5,15 @@ WITH inserted_feed_follow AS (
     created_at,
     updated_at,
     user_id,
-    feed_id
+    feed_id,
+    category_id
   ) VALUES (  
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
 RETURNING *
 )
@@ -26,15 +28,31 @@ INNER JOIN users
 ON inserted_feed_follow.user_id = users.id;
 
 -- name: GetFeedFollowsForUser :many
-SELECT feed_follows.*, feeds.name AS feed_name, users.name AS user_name
+SELECT feed_follows.*, feeds.name AS feed_name, users.name AS user_name,
+feeds.url AS feed_url, categories.name AS category_name
 FROM feed_follows
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
 INNER JOIN users ON feed_follows.user_id = users.id
-WHERE feed_follows.user_id = $1;
+LEFT JOIN categories ON feed_follows.category_id = categories.id
+WHERE feed_follows.user_id = $1
+ORDER BY categories.name NULLS FIRST, feeds.name;
 
 -- name: DeleteFeedFollowByUserAndFeedUrl :exec
 DELETE FROM feed_follows
 WHERE feed_follows.user_id = $1
 AND feed_follows.feed_id = (SELECT id FROM feeds WHERE url = $2);
 
+-- name: MoveFeedFollows :exec
+UPDATE feed_follows
+SET feed_id = sqlc.arg(target_id), updated_at = now()
+WHERE feed_follows.feed_id = sqlc.arg(source_id)
+AND feed_follows.user_id NOT IN (
+  SELECT target_follows.user_id FROM feed_follows AS target_follows
+  WHERE target_follows.feed_id = sqlc.arg(target_id)
+);
 
+-- name: SetFeedFollowCategory :exec
+UPDATE feed_follows
+SET category_id = $3, updated_at = now()
+WHERE feed_follows.user_id = $1
+AND feed_follows.feed_id = $2;
@@ -0,0 +1,19 @@
+-- name: CreateFeedUrlChange :one
+INSERT INTO feed_url_changes (id, created_at, feed_id, old_url, new_url, status_code, merged)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetFeedUrlChangesSince :many
+SELECT feed_url_changes.*, feeds.name AS feed_name
+FROM feed_url_changes
+INNER JOIN feeds ON feed_url_changes.feed_id = feeds.id
+WHERE feed_url_changes.created_at > $1
+ORDER BY feed_url_changes.created_at DESC;
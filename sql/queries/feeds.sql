@@ -24,13 +24,81 @@ ORDER By feeds.created_at DESC;
 SELECT * FROM feeds
 WHERE feeds.url = $1;
 
--- name: MarkFeedFetched :exec
-UPDATE feeds 
-SET updated_at = now(), last_fetched_at = now()
+-- name: UpdateFeedCacheHeaders :exec
+UPDATE feeds
+SET etag = $2, last_modified = $3
 WHERE feeds.id = $1;
 
--- name: GetNextFeedToFetch :one
-SELECT * FROM feeds
-ORDER BY feeds.last_fetched_at ASC NULLS FIRST
-LIMIT 1;
+-- name: ClaimFeedsToFetch :many
+UPDATE feeds
+SET updated_at = now(),
+    claimed_until = now() + (sqlc.arg(lease_seconds)::int * interval '1 second')
+WHERE feeds.id IN (
+  SELECT id FROM feeds
+  WHERE (feeds.claimed_until IS NULL OR feeds.claimed_until < now())
+  AND feeds.disabled_at IS NULL
+  AND (feeds.next_fetch_at IS NULL OR feeds.next_fetch_at <= now())
+  ORDER BY feeds.last_fetched_at ASC NULLS FIRST
+  LIMIT sqlc.arg(max_feeds)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RenewFeedLease :one
+UPDATE feeds
+SET claimed_until = now() + (sqlc.arg(lease_seconds)::int * interval '1 second')
+WHERE feeds.id = sqlc.arg(id)
+AND feeds.claimed_until = sqlc.arg(claimed_until)
+RETURNING feeds.claimed_until;
+
+-- name: ReleaseFeed :exec
+UPDATE feeds
+SET claimed_until = NULL
+WHERE feeds.id = $1
+AND feeds.claimed_until = $2;
+
+-- name: MarkFeedFetchSucceeded :exec
+UPDATE feeds
+SET updated_at = now(),
+    last_fetched_at = now(),
+    last_success_at = now(),
+    consecutive_failures = 0,
+    fetch_interval_seconds = sqlc.arg(fetch_interval_seconds),
+    next_fetch_at = now() + (sqlc.arg(next_fetch_in_seconds)::int * interval '1 second')
+WHERE feeds.id = sqlc.arg(id);
+
+-- name: MarkFeedFetchFailed :exec
+UPDATE feeds
+SET updated_at = now(),
+    last_fetched_at = now(),
+    consecutive_failures = feeds.consecutive_failures + 1,
+    next_fetch_at = now() + (sqlc.arg(retry_in_seconds)::int * interval '1 second')
+WHERE feeds.id = sqlc.arg(id);
+
+-- name: DisableFeed :exec
+UPDATE feeds
+SET updated_at = now(), disabled_at = now()
+WHERE feeds.id = $1;
+
+-- name: EnableFeed :one
+UPDATE feeds
+SET updated_at = now(),
+    disabled_at = NULL,
+    next_fetch_at = NULL,
+    consecutive_failures = 0
+WHERE feeds.url = $1
+RETURNING *;
+
+-- name: UpdateFeedUrl :exec
+UPDATE feeds
+SET updated_at = now(), url = $2
+WHERE feeds.id = $1;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE feeds.id = $1;
 
+-- name: SetFeedRetention :exec
+UPDATE feeds
+SET retention_max_age_seconds = $2, retention_keep_last = $3
+WHERE feeds.id = $1;
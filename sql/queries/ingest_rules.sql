@@ -0,0 +1,53 @@
+-- name: CreateIngestRule :one
+INSERT INTO ingest_rules (id, created_at, user_id, name, position, feed_id, category_id, title_pattern, min_age_seconds, max_age_seconds, action, tag, stop)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+)
+RETURNING *;
+
+-- name: GetIngestRulesForUser :many
+SELECT ingest_rules.*, feeds.name AS feed_name, categories.name AS category_name FROM ingest_rules
+LEFT JOIN feeds ON feeds.id = ingest_rules.feed_id
+LEFT JOIN categories ON categories.id = ingest_rules.category_id
+WHERE ingest_rules.user_id = $1
+ORDER BY ingest_rules.position, ingest_rules.created_at;
+
+-- name: GetIngestRuleByName :one
+SELECT * FROM ingest_rules
+WHERE ingest_rules.user_id = $1
+AND ingest_rules.name = $2;
+
+-- name: GetIngestRulesForFeed :many
+SELECT ingest_rules.*, feed_follows.category_id AS follow_category_id FROM ingest_rules
+INNER JOIN feed_follows ON feed_follows.user_id = ingest_rules.user_id
+WHERE feed_follows.feed_id = $1
+ORDER BY ingest_rules.user_id, ingest_rules.position, ingest_rules.created_at;
+
+-- name: DeleteIngestRule :execrows
+DELETE FROM ingest_rules
+WHERE ingest_rules.user_id = $1
+AND ingest_rules.name = $2;
+
+-- name: MoveIngestRulesToFeed :exec
+UPDATE ingest_rules
+SET feed_id = sqlc.arg(target_id)
+WHERE ingest_rules.feed_id = sqlc.arg(source_id);
+
+-- name: GetIngestRuleNamesForCategory :many
+SELECT ingest_rules.name FROM ingest_rules
+INNER JOIN categories ON categories.id = ingest_rules.category_id
+WHERE categories.user_id = $1
+AND categories.name = $2
+ORDER BY ingest_rules.position, ingest_rules.created_at;
@@ -0,0 +1,21 @@
+-- name: CreatePostEnclosure :one
+INSERT INTO post_enclosures (id, created_at, updated_at, post_id, url, mime_type, length, duration, episode, image_url)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+ON CONFLICT (post_id, url) DO NOTHING
+RETURNING *;
+
+-- name: GetEnclosuresForPost :many
+SELECT * FROM post_enclosures
+WHERE post_enclosures.post_id = $1
+ORDER BY post_enclosures.created_at;
@@ -0,0 +1,32 @@
+-- name: CreatePostFilter :one
+INSERT INTO post_filters (id, created_at, user_id, feed_id, field, pattern, is_regex, action)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+RETURNING *;
+
+-- name: GetPostFiltersForUser :many
+SELECT post_filters.*, feeds.name AS feed_name FROM post_filters
+LEFT JOIN feeds ON feeds.id = post_filters.feed_id
+WHERE post_filters.user_id = $1
+ORDER BY post_filters.created_at;
+
+-- name: DeletePostFilter :execrows
+DELETE FROM post_filters
+WHERE post_filters.id = $1
+AND post_filters.user_id = $2;
+
+-- name: CheckFilterRegex :exec
+SELECT '' ~* sqlc.arg(pattern)::text;
+
+-- name: MovePostFiltersToFeed :exec
+UPDATE post_filters
+SET feed_id = sqlc.arg(target_id)
+WHERE post_filters.feed_id = sqlc.arg(source_id);
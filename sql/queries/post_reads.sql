@@ -0,0 +1,29 @@
+-- name: MarkPostRead :execrows
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostsRead :execrows
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT sqlc.arg(user_id)::uuid, posts.id, sqlc.arg(read_at)::timestamp FROM posts
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)::uuid
+AND (sqlc.narg(feed)::text IS NULL OR feeds.name = sqlc.narg(feed) OR feeds.url = sqlc.narg(feed))
+AND (sqlc.narg(published_before)::timestamp IS NULL OR posts.published_at < sqlc.narg(published_before))
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :execrows
+DELETE FROM post_reads
+WHERE post_reads.user_id = $1
+AND post_reads.post_id = $2;
+
+-- name: MoveReadsToFeed :exec
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT post_reads.user_id, target_posts.id, post_reads.read_at
+FROM post_reads
+INNER JOIN posts AS source_posts ON source_posts.id = post_reads.post_id
+INNER JOIN posts AS target_posts ON target_posts.guid = source_posts.guid
+WHERE source_posts.feed_id = sqlc.arg(source_id)
+AND target_posts.feed_id = sqlc.arg(target_id)
+ON CONFLICT (user_id, post_id) DO NOTHING;
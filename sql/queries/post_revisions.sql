@@ -0,0 +1,17 @@
+-- name: CreatePostRevision :one
+INSERT INTO post_revisions (id, created_at, post_id, title, url, description, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetRevisionsForPost :many
+SELECT * FROM post_revisions
+WHERE post_revisions.post_id = $1
+ORDER BY post_revisions.created_at;
@@ -0,0 +1,20 @@
+-- name: TagPost :exec
+INSERT INTO post_tags (user_id, post_id, tag, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, post_id, tag) DO NOTHING;
+
+-- name: GetTagsForPost :many
+SELECT post_tags.tag FROM post_tags
+WHERE post_tags.user_id = $1
+AND post_tags.post_id = $2
+ORDER BY post_tags.tag;
+
+-- name: MoveTagsToFeed :exec
+INSERT INTO post_tags (user_id, post_id, tag, created_at)
+SELECT post_tags.user_id, target_posts.id, post_tags.tag, post_tags.created_at
+FROM post_tags
+INNER JOIN posts AS source_posts ON source_posts.id = post_tags.post_id
+INNER JOIN posts AS target_posts ON target_posts.guid = source_posts.guid
+WHERE source_posts.feed_id = sqlc.arg(source_id)
+AND target_posts.feed_id = sqlc.arg(target_id)
+ON CONFLICT (user_id, post_id, tag) DO NOTHING;
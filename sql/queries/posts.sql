@@ -1,5 +1,5 @@
 -- name: CreatePost :one
-INSERT INTO posts(id, created_at, updated_at, published_at, title, url, description, feed_id)
+INSERT INTO posts(id, created_at, updated_at, published_at, title, url, description, feed_id, content, guid, content_hash, source_updated_at, author)
 VALUES (
     $1,
     $2,
@@ -8,11 +8,119 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 )
+ON CONFLICT (feed_id, guid) DO NOTHING
 RETURNING *;
 
--- name: GetPostsForUser :many
+-- name: AdoptLegacyPostGuid :execrows
+UPDATE posts
+SET guid = sqlc.arg(guid)
+WHERE posts.feed_id = sqlc.arg(feed_id)
+AND posts.url = sqlc.arg(url)
+AND posts.guid = posts.url
+AND NOT EXISTS (
+  SELECT 1 FROM posts AS existing
+  WHERE existing.feed_id = sqlc.arg(feed_id)
+  AND existing.guid = sqlc.arg(guid)
+);
+
+-- name: GetPost :one
 SELECT * FROM posts
-ORDER BY posts.published_at 
-LIMIT $1;
+WHERE posts.id = $1;
+
+-- name: GetPostByFeedAndGuid :one
+SELECT * FROM posts
+WHERE posts.feed_id = $1
+AND posts.guid = $2;
+
+-- name: SearchPosts :many
+SELECT posts.id, posts.title, posts.url, posts.published_at, feeds.name AS feed_name,
+  ts_rank(posts.search_vector, query)::real AS rank,
+  ts_headline(
+    'english',
+    coalesce(nullif(posts.content, ''), nullif(posts.description, ''), posts.title, ''),
+    query,
+    'StartSel=**, StopSel=**, MaxFragments=2, MaxWords=20, MinWords=8'
+  )::text AS snippet
+FROM posts
+CROSS JOIN websearch_to_tsquery('english', sqlc.arg(query)) AS query
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND posts.search_vector @@ query
+AND (sqlc.narg(feed)::text IS NULL OR feeds.name = sqlc.narg(feed) OR feeds.url = sqlc.narg(feed))
+AND (sqlc.narg(before)::timestamp IS NULL OR posts.published_at < sqlc.narg(before))
+AND (sqlc.narg(after)::timestamp IS NULL OR posts.published_at >= sqlc.narg(after))
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: UpdatePost :one
+UPDATE posts
+SET title = $2,
+    url = $3,
+    description = $4,
+    content = $5,
+    content_hash = $6,
+    source_updated_at = $7,
+    updated_at = $8,
+    edited_at = $8
+WHERE posts.id = $1
+RETURNING *;
+
+-- name: GetPostsForUser :many
+SELECT sqlc.embed(posts), coalesce(filters.highlighted, false)::boolean AS highlighted FROM posts
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+INNER JOIN feeds ON feeds.id = posts.feed_id
+CROSS JOIN LATERAL (
+  SELECT bool_or(post_filters.action = 'hide') AS hidden, bool_or(post_filters.action = 'highlight') AS highlighted
+  FROM post_filters
+  WHERE post_filters.user_id = feed_follows.user_id
+  AND (post_filters.feed_id IS NULL OR post_filters.feed_id = posts.feed_id)
+  AND EXISTS (
+    SELECT 1 FROM unnest(CASE post_filters.field
+      WHEN 'title' THEN ARRAY[posts.title]
+      WHEN 'description' THEN ARRAY[posts.description]
+      WHEN 'author' THEN ARRAY[posts.author]
+      ELSE ARRAY[posts.title, posts.description, posts.author]
+    END) AS field_value
+    WHERE CASE WHEN post_filters.is_regex THEN field_value ~* post_filters.pattern
+      ELSE strpos(lower(field_value), lower(post_filters.pattern)) > 0
+    END
+  )
+) AS filters
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND (sqlc.arg(include_hidden)::boolean OR NOT coalesce(filters.hidden, false))
+AND (sqlc.narg(feed)::text IS NULL OR feeds.name = sqlc.narg(feed) OR feeds.url = sqlc.narg(feed))
+AND (sqlc.narg(category)::text IS NULL OR feed_follows.category_id = (
+  SELECT categories.id FROM categories
+  WHERE categories.user_id = feed_follows.user_id
+  AND categories.name = sqlc.narg(category)
+))
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+AND (sqlc.narg(before_id)::uuid IS NULL OR (posts.published_at, posts.id) < (
+  SELECT cursor_posts.published_at, cursor_posts.id FROM posts AS cursor_posts
+  WHERE cursor_posts.id = sqlc.narg(before_id)
+))
+AND (sqlc.arg(include_read)::boolean OR NOT EXISTS (
+  SELECT 1 FROM post_reads
+  WHERE post_reads.user_id = feed_follows.user_id
+  AND post_reads.post_id = posts.id
+))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT sqlc.arg(max_posts);
+
+-- name: MoveFeedPosts :exec
+UPDATE posts
+SET feed_id = sqlc.arg(target_id)
+WHERE posts.feed_id = sqlc.arg(source_id)
+AND posts.guid NOT IN (
+  SELECT target_posts.guid FROM posts AS target_posts
+  WHERE target_posts.feed_id = sqlc.arg(target_id)
+);
@@ -0,0 +1,71 @@
+-- name: CountExpiredPosts :many
+WITH policies AS (
+  SELECT feeds.id,
+    CASE WHEN feeds.retention_max_age_seconds IS NULL AND feeds.retention_keep_last IS NULL
+      THEN sqlc.arg(default_max_age_seconds)::int
+      ELSE coalesce(feeds.retention_max_age_seconds, 0)
+    END AS max_age_seconds,
+    CASE WHEN feeds.retention_max_age_seconds IS NULL AND feeds.retention_keep_last IS NULL
+      THEN sqlc.arg(default_keep_last)::int
+      ELSE coalesce(feeds.retention_keep_last, 0)
+    END AS keep_last
+  FROM feeds
+), ranked AS (
+  SELECT posts.id, posts.feed_id, posts.published_at,
+    row_number() OVER (PARTITION BY posts.feed_id ORDER BY posts.published_at DESC, posts.id DESC) AS position
+  FROM posts
+), expired AS (
+  SELECT ranked.id, ranked.feed_id FROM ranked
+  INNER JOIN policies ON policies.id = ranked.feed_id
+  WHERE (
+    (policies.max_age_seconds > 0 AND ranked.published_at < now() - (policies.max_age_seconds * interval '1 second'))
+    OR (policies.keep_last > 0 AND ranked.position > policies.keep_last)
+  )
+  AND NOT EXISTS (SELECT 1 FROM starred_posts WHERE starred_posts.post_id = ranked.id)
+)
+SELECT feeds.name AS feed_name, count(*)::int AS posts FROM expired
+INNER JOIN feeds ON feeds.id = expired.feed_id
+GROUP BY feeds.name
+ORDER BY feeds.name;
+
+-- name: PruneExpiredPosts :many
+WITH policies AS (
+  SELECT feeds.id,
+    CASE WHEN feeds.retention_max_age_seconds IS NULL AND feeds.retention_keep_last IS NULL
+      THEN sqlc.arg(default_max_age_seconds)::int
+      ELSE coalesce(feeds.retention_max_age_seconds, 0)
+    END AS max_age_seconds,
+    CASE WHEN feeds.retention_max_age_seconds IS NULL AND feeds.retention_keep_last IS NULL
+      THEN sqlc.arg(default_keep_last)::int
+      ELSE coalesce(feeds.retention_keep_last, 0)
+    END AS keep_last
+  FROM feeds
+), ranked AS (
+  SELECT posts.id, posts.feed_id, posts.published_at,
+    row_number() OVER (PARTITION BY posts.feed_id ORDER BY posts.published_at DESC, posts.id DESC) AS position
+  FROM posts
+), expired AS (
+  SELECT ranked.id, ranked.feed_id FROM ranked
+  INNER JOIN policies ON policies.id = ranked.feed_id
+  WHERE (
+    (policies.max_age_seconds > 0 AND ranked.published_at < now() - (policies.max_age_seconds * interval '1 second'))
+    OR (policies.keep_last > 0 AND ranked.position > policies.keep_last)
+  )
+  AND NOT EXISTS (SELECT 1 FROM starred_posts WHERE starred_posts.post_id = ranked.id)
+), deleted AS (
+  DELETE FROM posts
+  USING expired
+  WHERE posts.id = expired.id
+  RETURNING posts.feed_id
+)
+SELECT feeds.name AS feed_name, count(*)::int AS posts FROM deleted
+INNER JOIN feeds ON feeds.id = deleted.feed_id
+GROUP BY feeds.name
+ORDER BY feeds.name;
+
+-- name: GetKeepLastCutoff :one
+SELECT posts.published_at FROM posts
+WHERE posts.feed_id = sqlc.arg(feed_id)
+ORDER BY posts.published_at DESC, posts.id DESC
+OFFSET sqlc.arg(keep_last)::int - 1
+LIMIT 1;
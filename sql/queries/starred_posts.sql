@@ -0,0 +1,28 @@
+-- name: StarPost :one
+INSERT INTO starred_posts (user_id, post_id, created_at, note)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET note = COALESCE(EXCLUDED.note, starred_posts.note)
+RETURNING *;
+
+-- name: UnstarPost :execrows
+DELETE FROM starred_posts
+WHERE starred_posts.user_id = $1
+AND starred_posts.post_id = $2;
+
+-- name: GetStarredPostsForUser :many
+SELECT posts.*, starred_posts.note, starred_posts.created_at AS starred_at, feeds.name AS feed_name FROM starred_posts
+INNER JOIN posts ON posts.id = starred_posts.post_id
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE starred_posts.user_id = $1
+ORDER BY starred_posts.created_at DESC;
+
+-- name: MoveStarsToFeed :exec
+INSERT INTO starred_posts (user_id, post_id, created_at, note)
+SELECT starred_posts.user_id, target_posts.id, starred_posts.created_at, starred_posts.note
+FROM starred_posts
+INNER JOIN posts AS source_posts ON source_posts.id = starred_posts.post_id
+INNER JOIN posts AS target_posts ON target_posts.guid = source_posts.guid
+WHERE source_posts.feed_id = sqlc.arg(source_id)
+AND target_posts.feed_id = sqlc.arg(target_id)
+ON CONFLICT (user_id, post_id) DO NOTHING;
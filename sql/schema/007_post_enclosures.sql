@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE post_enclosures (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  mime_type TEXT,
+  length BIGINT,
+  duration TEXT,
+  episode TEXT,
+  image_url TEXT,
+  CONSTRAINT unique_post_enclosure
+  UNIQUE (post_id, url)
+);
+
+-- +goose Down
+DROP TABLE post_enclosures;
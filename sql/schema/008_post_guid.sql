@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN guid TEXT;
+
+UPDATE posts SET guid = COALESCE(url, id::text);
+
+ALTER TABLE posts
+ALTER COLUMN guid SET NOT NULL,
+DROP CONSTRAINT posts_url_key,
+ADD CONSTRAINT unique_feed_post_guid UNIQUE (feed_id, guid);
+
+-- +goose Down
+ALTER TABLE posts
+DROP CONSTRAINT unique_feed_post_guid,
+ADD CONSTRAINT posts_url_key UNIQUE (url),
+DROP COLUMN guid;
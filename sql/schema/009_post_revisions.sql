@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN content_hash TEXT,
+ADD COLUMN source_updated_at TIMESTAMP NULL DEFAULT NULL,
+ADD COLUMN edited_at TIMESTAMP NULL DEFAULT NULL;
+
+CREATE TABLE post_revisions (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+  title TEXT,
+  url TEXT,
+  description TEXT,
+  content TEXT
+);
+
+-- +goose Down
+DROP TABLE post_revisions;
+
+ALTER TABLE posts
+DROP COLUMN content_hash,
+DROP COLUMN source_updated_at,
+DROP COLUMN edited_at;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE feed_fetch_log (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+  status_code INT,
+  error TEXT,
+  duration_ms INT NOT NULL,
+  item_count INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX feed_fetch_log_feed_id_created_at_idx ON feed_fetch_log (feed_id, created_at);
+
+ALTER TABLE feeds
+ADD COLUMN consecutive_failures INT NOT NULL DEFAULT 0,
+ADD COLUMN last_success_at TIMESTAMP NULL DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN consecutive_failures,
+DROP COLUMN last_success_at;
+
+DROP TABLE feed_fetch_log;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN next_fetch_at TIMESTAMP NULL DEFAULT NULL,
+ADD COLUMN disabled_at TIMESTAMP NULL DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN next_fetch_at,
+DROP COLUMN disabled_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE feed_url_changes (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+  old_url TEXT NOT NULL,
+  new_url TEXT NOT NULL,
+  status_code INT NOT NULL,
+  merged BOOLEAN NOT NULL DEFAULT false
+);
+
+-- +goose Down
+DROP TABLE feed_url_changes;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE categories (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  CONSTRAINT unique_user_category
+  UNIQUE (user_id, name)
+);
+
+ALTER TABLE feed_follows
+ADD COLUMN category_id UUID NULL REFERENCES categories(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE feed_follows
+DROP COLUMN category_id;
+
+DROP TABLE categories;
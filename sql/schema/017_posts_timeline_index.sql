@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX posts_feed_published_idx ON posts (feed_id, published_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX posts_feed_published_idx;
@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN author TEXT NULL;
+
+CREATE TABLE post_filters (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  feed_id UUID NULL REFERENCES feeds(id) ON DELETE CASCADE,
+  field TEXT NOT NULL CHECK (field IN ('title', 'description', 'author', 'any')),
+  pattern TEXT NOT NULL,
+  is_regex BOOLEAN NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('hide', 'highlight'))
+);
+
+-- +goose Down
+DROP TABLE post_filters;
+
+ALTER TABLE posts
+DROP COLUMN author;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE ingest_rules (
+  id UUID PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  position INT NOT NULL,
+  feed_id UUID NULL REFERENCES feeds(id) ON DELETE CASCADE,
+  category_id UUID NULL REFERENCES categories(id) ON DELETE CASCADE,
+  title_pattern TEXT NULL,
+  min_age_seconds INT NULL,
+  max_age_seconds INT NULL,
+  action TEXT NOT NULL CHECK (action IN ('mark-read', 'star', 'tag', 'notify')),
+  tag TEXT NULL,
+  stop BOOLEAN NOT NULL DEFAULT FALSE,
+  CONSTRAINT unique_user_rule
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE post_tags (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+  tag TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, post_id, tag)
+);
+
+-- +goose Down
+DROP TABLE post_tags;
+
+DROP TABLE ingest_rules;
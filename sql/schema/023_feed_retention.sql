@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN retention_max_age_seconds INT NULL,
+ADD COLUMN retention_keep_last INT NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN retention_max_age_seconds,
+DROP COLUMN retention_keep_last;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE ingest_rules
+DROP CONSTRAINT ingest_rules_category_id_fkey,
+ADD CONSTRAINT ingest_rules_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories(id);
+
+-- +goose Down
+ALTER TABLE ingest_rules
+DROP CONSTRAINT ingest_rules_category_id_fkey,
+ADD CONSTRAINT ingest_rules_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;